@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/pivotal-golang/lager"
 	"github.com/tedsuo/rata"
@@ -12,7 +13,12 @@ import (
 	"github.com/concourse/turbine/api/deletebuild"
 	"github.com/concourse/turbine/api/events"
 	"github.com/concourse/turbine/api/execute"
+	"github.com/concourse/turbine/api/exportevents"
+	"github.com/concourse/turbine/api/getbuild"
+	"github.com/concourse/turbine/api/getlog"
 	"github.com/concourse/turbine/api/hijack"
+	"github.com/concourse/turbine/api/plan"
+	"github.com/concourse/turbine/config"
 	"github.com/concourse/turbine/resource"
 	"github.com/concourse/turbine/scheduler"
 )
@@ -21,19 +27,31 @@ func New(
 	logger lager.Logger,
 	scheduler scheduler.Scheduler,
 	tracker resource.Tracker,
+	resourceTypes config.ResourceTypes,
+	checkCacheTTL time.Duration,
+	eventKeepaliveInterval time.Duration,
+	clock scheduler.Clock,
 	turbineEndpoint string,
 	drain <-chan struct{},
 ) (http.Handler, error) {
-	checkHandler := check.NewHandler(logger, tracker, drain)
+	checker := resource.NewChecker(tracker, checkCacheTTL, drain)
+	checkHandler := check.NewHandler(logger, tracker, checker, clock, drain)
+	eventsHandler := events.NewHandler(logger, scheduler, clock, eventKeepaliveInterval)
 
 	handlers := map[string]http.Handler{
-		turbine.ExecuteBuild:     execute.NewHandler(logger, scheduler, turbineEndpoint),
-		turbine.DeleteBuild:      deletebuild.NewHandler(logger, scheduler),
-		turbine.AbortBuild:       abort.NewHandler(logger, scheduler),
-		turbine.HijackBuild:      hijack.NewHandler(logger, scheduler),
-		turbine.GetBuildEvents:   events.NewHandler(logger, scheduler),
-		turbine.CheckInput:       checkHandler,
-		turbine.CheckInputStream: http.HandlerFunc(checkHandler.Stream),
+		turbine.ExecuteBuild:      execute.NewHandler(logger, scheduler, resourceTypes, turbineEndpoint),
+		turbine.PlanBuild:         plan.NewHandler(logger, tracker, resourceTypes, drain),
+		turbine.GetBuild:          getbuild.NewHandler(logger, scheduler),
+		turbine.DeleteBuild:       deletebuild.NewHandler(logger, scheduler),
+		turbine.AbortBuild:        abort.NewHandler(logger, scheduler),
+		turbine.HijackBuild:       hijack.NewHandler(logger, scheduler),
+		turbine.GetBuildEvents:    eventsHandler,
+		turbine.StreamBuildEvents: http.HandlerFunc(eventsHandler.Stream),
+		turbine.ExportBuildEvents: exportevents.NewHandler(logger, scheduler),
+		turbine.GetBuildLog:       getlog.NewHandler(logger, scheduler),
+		turbine.CheckInput:        checkHandler,
+		turbine.CheckInputStream:  http.HandlerFunc(checkHandler.Stream),
+		turbine.WatchInput:        http.HandlerFunc(checkHandler.Watch),
 	}
 
 	return rata.NewRouter(turbine.Routes, handlers)
@@ -2,10 +2,12 @@ package api_test
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/gorilla/websocket"
 
 	"github.com/concourse/turbine"
+	rsrc "github.com/concourse/turbine/resource"
 	"github.com/concourse/turbine/resource/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -49,7 +51,7 @@ var _ = Describe("GET /checks/stream", func() {
 			BeforeEach(func() {
 				resource = new(fakes.FakeResource)
 
-				tracker.InitReturns(resource, nil)
+				tracker.InitForCheckReturns(resource, nil)
 			})
 
 			Context("and the resource yields versions", func() {
@@ -73,9 +75,9 @@ var _ = Describe("GET /checks/stream", func() {
 
 			Describe("draining", func() {
 				It("aborts the check", func() {
-					Eventually(tracker.InitCallCount).Should(Equal(1))
+					Eventually(tracker.InitForCheckCallCount).Should(Equal(1))
 
-					_, _, abort := tracker.InitArgsForCall(0)
+					_, _, abort := tracker.InitForCheckArgsForCall(0)
 
 					Ω(abort).ShouldNot(BeClosed())
 
@@ -90,11 +92,42 @@ var _ = Describe("GET /checks/stream", func() {
 					resource.CheckReturns(nil, errors.New("oh no!"))
 				})
 
-				It("closes the connection", func() {
-					Eventually(func() error {
-						_, _, err := conn.ReadMessage()
-						return err
-					}).Should(HaveOccurred())
+				It("sends the error and closes the connection", func() {
+					var checkErr turbine.CheckError
+					err := conn.ReadJSON(&checkErr)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(checkErr).Should(Equal(turbine.CheckError{
+						Code:    turbine.CheckErrorInternal,
+						Message: "oh no!",
+					}))
+
+					_, _, err = conn.ReadMessage()
+					Ω(websocket.IsCloseError(err, websocket.CloseInternalServerErr)).Should(BeTrue())
+					Ω(err.(*websocket.CloseError).Text).Should(Equal(string(turbine.CheckErrorInternal)))
+				})
+			})
+
+			Context("when the check script fails with a lot of output", func() {
+				BeforeEach(func() {
+					resource.CheckReturns(nil, rsrc.ErrResourceScriptFailed{
+						Path:       "/opt/resource/check",
+						Stdout:     strings.Repeat("o", 1024),
+						Stderr:     strings.Repeat("e", 1024),
+						ExitStatus: 1,
+					})
+				})
+
+				It("sends the full error, and still closes the connection", func() {
+					var checkErr turbine.CheckError
+					err := conn.ReadJSON(&checkErr)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(checkErr.Stderr).Should(Equal(strings.Repeat("e", 1024)))
+
+					_, _, err = conn.ReadMessage()
+					Ω(websocket.IsCloseError(err, websocket.CloseInternalServerErr)).Should(BeTrue())
+					Ω(err.(*websocket.CloseError).Text).Should(Equal(string(turbine.CheckErrorScriptFailed)))
 				})
 			})
 
@@ -111,7 +144,15 @@ var _ = Describe("GET /checks/stream", func() {
 
 		Context("when initializing the resource fails", func() {
 			BeforeEach(func() {
-				tracker.InitReturns(nil, errors.New("oh no!"))
+				tracker.InitForCheckReturns(nil, rsrc.ErrUnknownResourceType)
+			})
+
+			It("sends the error", func() {
+				var checkErr turbine.CheckError
+				err := conn.ReadJSON(&checkErr)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(checkErr.Code).Should(Equal(turbine.CheckErrorUnknownResourceType))
 			})
 
 			It("closes the connection", func() {
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/concourse/turbine"
+	rsrc "github.com/concourse/turbine/resource"
 	"github.com/concourse/turbine/resource/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -48,13 +50,21 @@ var _ = Describe("POST /checks", func() {
 		Ω(err).ShouldNot(HaveOccurred())
 	})
 
+	responseError := func() turbine.CheckError {
+		var checkErr turbine.CheckError
+		err := json.NewDecoder(response.Body).Decode(&checkErr)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		return checkErr
+	}
+
 	Context("when initializing the resource succeeds", func() {
 		var resource *fakes.FakeResource
 
 		BeforeEach(func() {
 			resource = new(fakes.FakeResource)
 
-			tracker.InitReturns(resource, nil)
+			tracker.InitForCheckReturns(resource, nil)
 		})
 
 		Context("and checking succeeds", func() {
@@ -94,6 +104,15 @@ var _ = Describe("POST /checks", func() {
 				Ω(tracker.ReleaseCallCount()).Should(Equal(1))
 				Ω(tracker.ReleaseArgsForCall(0)).Should(Equal(resource))
 			})
+
+			It("aborts the check when draining", func() {
+				_, _, abort := tracker.InitForCheckArgsForCall(0)
+				Ω(abort).ShouldNot(BeClosed())
+
+				close(drain)
+
+				Ω(abort).Should(BeClosed())
+			})
 		})
 
 		Context("and checking fails", func() {
@@ -101,12 +120,107 @@ var _ = Describe("POST /checks", func() {
 				resource.CheckReturns(nil, errors.New("oh no!"))
 			})
 
-			It("returns 500", func() {
+			It("returns 500 with an internal error", func() {
 				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				Ω(response.Header.Get("Content-Type")).Should(Equal("application/json"))
+
+				Ω(responseError()).Should(Equal(turbine.CheckError{
+					Code:    turbine.CheckErrorInternal,
+					Message: "oh no!",
+				}))
+			})
+		})
+
+		Context("and the check script fails", func() {
+			var scriptErr rsrc.ErrResourceScriptFailed
+
+			BeforeEach(func() {
+				scriptErr = rsrc.ErrResourceScriptFailed{
+					Path:       "/opt/resource/check",
+					Stdout:     "some-stdout",
+					Stderr:     "some-stderr",
+					ExitStatus: 3,
+				}
+
+				resource.CheckReturns(nil, scriptErr)
+			})
+
+			It("returns 502 with the script's output and exit status", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusBadGateway))
+
+				Ω(responseError()).Should(Equal(turbine.CheckError{
+					Code:       turbine.CheckErrorScriptFailed,
+					Message:    scriptErr.Error(),
+					ExitStatus: 3,
+					Stdout:     "some-stdout",
+					Stderr:     "some-stderr",
+				}))
+			})
+		})
+
+		Context("and the check script's output is malformed", func() {
+			BeforeEach(func() {
+				resource.CheckReturns(nil, rsrc.ErrResourceScriptMalformedOutput{
+					Path:   "/opt/resource/check",
+					Stdout: "ß",
+					Err:    errors.New("invalid character"),
+				})
+			})
+
+			It("returns 502 with the output", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusBadGateway))
+
+				checkErr := responseError()
+				Ω(checkErr.Code).Should(Equal(turbine.CheckErrorMalformedOutput))
+				Ω(checkErr.Stdout).Should(Equal("ß"))
+			})
+		})
+
+		Context("and checking times out", func() {
+			BeforeEach(func() {
+				resource.CheckReturns(nil, rsrc.ErrResourceScriptTimedOut{
+					Path:    "/opt/resource/check",
+					Timeout: time.Minute,
+				})
+			})
+
+			It("returns 504", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusGatewayTimeout))
+				Ω(responseError().Code).Should(Equal(turbine.CheckErrorScriptTimedOut))
 			})
 		})
 	})
 
+	Context("when the resource type is unknown", func() {
+		BeforeEach(func() {
+			tracker.InitForCheckReturns(nil, rsrc.ErrUnknownResourceType)
+		})
+
+		It("returns 400", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+
+			Ω(responseError()).Should(Equal(turbine.CheckError{
+				Code:    turbine.CheckErrorUnknownResourceType,
+				Message: "unknown resource type",
+			}))
+		})
+	})
+
+	Context("when creating the container fails", func() {
+		BeforeEach(func() {
+			tracker.InitForCheckReturns(nil, rsrc.ErrCreatingContainer{Err: errors.New("oh no!")})
+		})
+
+		It("returns 500", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+
+			Ω(responseError()).Should(Equal(turbine.CheckError{
+				Code:    turbine.CheckErrorContainerCreationFailed,
+				Message: "failed to create container: oh no!",
+			}))
+		})
+	})
+
 	Context("when the payload is malformed JSON", func() {
 		BeforeEach(func() {
 			requestBody = "ß"
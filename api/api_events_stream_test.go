@@ -0,0 +1,174 @@
+package api_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/concourse/turbine/event"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GET /builds/:guid/events/stream", func() {
+	var (
+		events chan event.Event
+		stop   chan struct{}
+
+		path string
+
+		conn     *websocket.Conn
+		response *http.Response
+		dialErr  error
+	)
+
+	BeforeEach(func() {
+		events = make(chan event.Event, 10)
+		stop = make(chan struct{})
+
+		scheduler.SubscribeReturns(events, stop, nil)
+
+		path = "/builds/some-build-guid/events/stream"
+	})
+
+	JustBeforeEach(func() {
+		dialer := &websocket.Dialer{}
+
+		conn, response, dialErr = dialer.Dial("ws://"+server.Listener.Addr().String()+path, nil)
+	})
+
+	AfterEach(func() {
+		if conn != nil {
+			conn.Close()
+		}
+	})
+
+	readMessage := func() event.Message {
+		var message event.Message
+		err := conn.ReadJSON(&message)
+		Ω(err).ShouldNot(HaveOccurred())
+		return message
+	}
+
+	It("subscribes to the build via the scheduler", func() {
+		Ω(dialErr).ShouldNot(HaveOccurred())
+
+		Ω(scheduler.SubscribeCallCount()).Should(Equal(1))
+
+		guid, from := scheduler.SubscribeArgsForCall(0)
+		Ω(guid).Should(Equal("some-build-guid"))
+		Ω(from).Should(Equal(uint(0)))
+	})
+
+	It("responds with the event stream version", func() {
+		Ω(dialErr).ShouldNot(HaveOccurred())
+		Ω(response.Header.Get("X-Turbine-Event-Version")).Should(Equal(string(event.CURRENT_VERSION)))
+	})
+
+	Context("when events are emitted", func() {
+		BeforeEach(func() {
+			events <- event.CURRENT_VERSION
+			events <- event.Start{Timestamp: event.Timestamp{Time: 1}}
+			close(events)
+		})
+
+		It("sends them as messages with their ids, and then closes", func() {
+			Ω(dialErr).ShouldNot(HaveOccurred())
+
+			Ω(readMessage()).Should(Equal(event.Message{ID: "0", Event: event.CURRENT_VERSION}))
+			Ω(readMessage()).Should(Equal(event.Message{ID: "1", Event: event.Start{Timestamp: event.Timestamp{Time: 1}}}))
+
+			_, _, err := conn.ReadMessage()
+			Ω(websocket.IsCloseError(err, websocket.CloseNormalClosure)).Should(BeTrue())
+		})
+	})
+
+	Context("when resuming from an event id", func() {
+		BeforeEach(func() {
+			path += "?last_event_id=41&type=start"
+
+			events <- event.Log{Payload: "skipped"}
+			events <- event.Start{Timestamp: event.Timestamp{Time: 1}}
+		})
+
+		It("subscribes from after it, keeping ids of filtered events", func() {
+			Ω(dialErr).ShouldNot(HaveOccurred())
+
+			_, from := scheduler.SubscribeArgsForCall(0)
+			Ω(from).Should(Equal(uint(42)))
+
+			Ω(readMessage()).Should(Equal(event.Message{ID: "43", Event: event.Start{Timestamp: event.Timestamp{Time: 1}}}))
+		})
+	})
+
+	Context("when the stream is idle", func() {
+		var keepalive chan time.Time
+
+		BeforeEach(func() {
+			keepalive = make(chan time.Time)
+
+			// handlers may outlive the spec; don't share the variable with them
+			ch := keepalive
+			clock.AfterStub = func(d time.Duration) <-chan time.Time {
+				return ch
+			}
+		})
+
+		It("pings the client every keepalive interval", func() {
+			Ω(dialErr).ShouldNot(HaveOccurred())
+
+			pinged := make(chan string, 2)
+			conn.SetPingHandler(func(data string) error {
+				pinged <- data
+				return nil
+			})
+
+			go conn.ReadMessage()
+
+			keepalive <- time.Now()
+			Eventually(pinged).Should(Receive())
+
+			keepalive <- time.Now()
+			Eventually(pinged).Should(Receive())
+
+			Ω(clock.AfterArgsForCall(0)).Should(Equal(time.Minute))
+		})
+
+		It("restarts the interval after each event", func() {
+			Ω(dialErr).ShouldNot(HaveOccurred())
+
+			Eventually(clock.AfterCallCount).Should(Equal(1))
+
+			events <- event.Start{Timestamp: event.Timestamp{Time: 1}}
+			Ω(readMessage()).Should(Equal(event.Message{ID: "0", Event: event.Start{Timestamp: event.Timestamp{Time: 1}}}))
+
+			Eventually(clock.AfterCallCount).Should(Equal(2))
+			Ω(clock.AfterArgsForCall(1)).Should(Equal(time.Minute))
+		})
+	})
+
+	Context("when requesting an unsupported version", func() {
+		BeforeEach(func() {
+			path += "?version=0.9"
+		})
+
+		It("fails to upgrade with 400", func() {
+			Ω(dialErr).Should(HaveOccurred())
+			Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+			Ω(scheduler.SubscribeCallCount()).Should(BeZero())
+		})
+	})
+
+	Context("when subscribing fails", func() {
+		BeforeEach(func() {
+			scheduler.SubscribeReturns(nil, nil, errors.New("oh no!"))
+		})
+
+		It("fails to upgrade with 404", func() {
+			Ω(dialErr).Should(HaveOccurred())
+			Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -1,11 +1,14 @@
 package api_test
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/concourse/turbine/event"
 	. "github.com/onsi/ginkgo"
@@ -87,7 +90,7 @@ var _ = Describe("GET /builds/:guid/events", func() {
 		It("emits events from the subscription as server-sent events", func() {
 			reader := sse.NewReader(response.Body)
 
-			events <- event.Start{Time: 1}
+			events <- event.Start{Timestamp: event.Timestamp{Time: 1}}
 
 			ev, err := reader.Next()
 			Ω(err).ShouldNot(HaveOccurred())
@@ -99,9 +102,9 @@ var _ = Describe("GET /builds/:guid/events", func() {
 			err = json.Unmarshal(ev.Data, &message)
 			Ω(err).ShouldNot(HaveOccurred())
 
-			Ω(message).Should(Equal(event.Start{Time: 1}))
+			Ω(message).Should(Equal(event.Start{Timestamp: event.Timestamp{Time: 1}}))
 
-			events <- event.Start{Time: 2}
+			events <- event.Start{Timestamp: event.Timestamp{Time: 2}}
 
 			ev, err = reader.Next()
 			Ω(err).ShouldNot(HaveOccurred())
@@ -112,7 +115,7 @@ var _ = Describe("GET /builds/:guid/events", func() {
 			err = json.Unmarshal(ev.Data, &message)
 			Ω(err).ShouldNot(HaveOccurred())
 
-			Ω(message).Should(Equal(event.Start{Time: 2}))
+			Ω(message).Should(Equal(event.Start{Timestamp: event.Timestamp{Time: 2}}))
 		})
 
 		Context("when the event stream finishes", func() {
@@ -139,11 +142,11 @@ var _ = Describe("GET /builds/:guid/events", func() {
 
 		Context("when events already were emitted", func() {
 			BeforeEach(func() {
-				events <- event.Start{Time: 1}
-				events <- event.Start{Time: 2}
-				events <- event.Start{Time: 3}
-				events <- event.Start{Time: 4}
-				events <- event.Start{Time: 5}
+				events <- event.Start{Timestamp: event.Timestamp{Time: 1}}
+				events <- event.Start{Timestamp: event.Timestamp{Time: 2}}
+				events <- event.Start{Timestamp: event.Timestamp{Time: 3}}
+				events <- event.Start{Timestamp: event.Timestamp{Time: 4}}
+				events <- event.Start{Timestamp: event.Timestamp{Time: 5}}
 			})
 
 			It("emits them starting from the first one", func() {
@@ -160,7 +163,7 @@ var _ = Describe("GET /builds/:guid/events", func() {
 					err = json.Unmarshal(ev.Data, &message)
 					Ω(err).ShouldNot(HaveOccurred())
 
-					Ω(message).Should(Equal(event.Start{Time: 1 + int64(i)}))
+					Ω(message).Should(Equal(event.Start{Timestamp: event.Timestamp{Time: 1 + int64(i)}}))
 				}
 			})
 
@@ -183,13 +186,216 @@ var _ = Describe("GET /builds/:guid/events", func() {
 						err = json.Unmarshal(ev.Data, &message)
 						Ω(err).ShouldNot(HaveOccurred())
 
-						Ω(message).Should(Equal(event.Start{Time: 1 + int64(i)}))
+						Ω(message).Should(Equal(event.Start{Timestamp: event.Timestamp{Time: 1 + int64(i)}}))
 					}
 				})
 			})
 		})
 	})
 
+	Context("when filtering by type and origin", func() {
+		BeforeEach(func() {
+			var err error
+
+			request, err = http.NewRequest("GET", server.URL+"/builds/some-build-guid/events?type=log&origin=run&origin=input:repo", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			request.Header.Set("Last-Event-ID", "9")
+
+			events <- event.Log{Origin: event.Origin{Type: event.OriginTypeInput, Name: "other"}, Payload: "0"}
+			events <- event.Log{Origin: event.Origin{Type: event.OriginTypeInput, Name: "repo"}, Payload: "1"}
+			events <- event.Status{Status: "started"}
+			events <- event.Log{Origin: event.Origin{Type: event.OriginTypeRun, Name: "stdout"}, Payload: "3"}
+			events <- event.End{}
+		})
+
+		It("emits only the matching events, keeping their ids", func() {
+			reader := sse.NewReader(response.Body)
+
+			ev, err := reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.ID).Should(Equal("11"))
+
+			var message event.Log
+			err = json.Unmarshal(ev.Data, &message)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(message.Payload).Should(Equal("1"))
+
+			ev, err = reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.ID).Should(Equal("13"))
+
+			err = json.Unmarshal(ev.Data, &message)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(message.Payload).Should(Equal("3"))
+
+			ev, err = reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.ID).Should(Equal("14"))
+			Ω(ev.Name).Should(Equal(string(event.EventTypeEnd)))
+		})
+
+		Context("with an unknown origin type", func() {
+			BeforeEach(func() {
+				var err error
+
+				request, err = http.NewRequest("GET", server.URL+"/builds/some-build-guid/events?origin=bogus", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("returns 400", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("with an unknown event type", func() {
+			BeforeEach(func() {
+				var err error
+
+				request, err = http.NewRequest("GET", server.URL+"/builds/some-build-guid/events?type=log&type=bogus", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("returns 400 with the error", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(ContainSubstring(`unknown event type: "bogus"`))
+			})
+		})
+	})
+
+	Context("when requesting an older version", func() {
+		BeforeEach(func() {
+			var err error
+
+			request, err = http.NewRequest("GET", server.URL+"/builds/some-build-guid/events?version=1.1", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			events <- event.CURRENT_VERSION
+			events <- event.Progress{Origin: event.Origin{Type: event.OriginTypeInput, Name: "repo"}, Current: 1, Timestamp: event.Timestamp{Time: 42}}
+			events <- event.Log{Origin: event.Origin{Type: event.OriginTypeRun, Name: "stdout"}, Payload: "hi", Timestamp: event.Timestamp{Time: 42}}
+			events <- event.End{}
+		})
+
+		It("responds with the version", func() {
+			Ω(response.Header.Get("X-Turbine-Event-Version")).Should(Equal("1.1"))
+		})
+
+		It("emits the events in the version's schema, keeping their ids", func() {
+			reader := sse.NewReader(response.Body)
+
+			ev, err := reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.ID).Should(Equal("0"))
+			Ω(ev.Name).Should(Equal(string(event.EventTypeVersion)))
+			Ω(string(ev.Data)).Should(Equal(`"1.1"`))
+
+			ev, err = reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.ID).Should(Equal("2"))
+			Ω(ev.Name).Should(Equal(string(event.EventTypeLog)))
+			Ω(string(ev.Data)).Should(MatchJSON(`{"origin":{"type":"run","name":"stdout"},"payload":"hi"}`))
+
+			ev, err = reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.ID).Should(Equal("3"))
+			Ω(ev.Name).Should(Equal(string(event.EventTypeEnd)))
+		})
+
+		Context("via the X-Turbine-Event-Version header", func() {
+			BeforeEach(func() {
+				var err error
+
+				request, err = http.NewRequest("GET", server.URL+"/builds/some-build-guid/events", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				request.Header.Set("X-Turbine-Event-Version", "1.1")
+			})
+
+			It("responds with the version", func() {
+				Ω(response.Header.Get("X-Turbine-Event-Version")).Should(Equal("1.1"))
+			})
+		})
+	})
+
+	Context("when the stream is idle", func() {
+		var keepalive chan time.Time
+
+		BeforeEach(func() {
+			keepalive = make(chan time.Time)
+
+			// handlers may outlive the spec; don't share the variable with them
+			ch := keepalive
+			clock.AfterStub = func(d time.Duration) <-chan time.Time {
+				return ch
+			}
+		})
+
+		It("sends a keepalive comment every keepalive interval", func() {
+			reader := bufio.NewReader(response.Body)
+
+			keepalive <- time.Now()
+
+			line, err := reader.ReadString('\n')
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(line).Should(Equal(": keepalive\n"))
+
+			line, err = reader.ReadString('\n')
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(line).Should(Equal("\n"))
+
+			keepalive <- time.Now()
+
+			line, err = reader.ReadString('\n')
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(line).Should(Equal(": keepalive\n"))
+
+			Ω(clock.AfterArgsForCall(0)).Should(Equal(time.Minute))
+		})
+
+		It("restarts the interval after each event", func() {
+			Eventually(clock.AfterCallCount).Should(Equal(1))
+
+			events <- event.Start{Timestamp: event.Timestamp{Time: 1}}
+
+			reader := sse.NewReader(response.Body)
+
+			ev, err := reader.Next()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ev.Name).Should(Equal(string(event.EventTypeStart)))
+
+			Eventually(clock.AfterCallCount).Should(Equal(2))
+			Ω(clock.AfterArgsForCall(1)).Should(Equal(time.Minute))
+		})
+	})
+
+	Context("when no version is requested", func() {
+		It("responds with the current version", func() {
+			Ω(response.Header.Get("X-Turbine-Event-Version")).Should(Equal(string(event.CURRENT_VERSION)))
+		})
+	})
+
+	Context("when requesting an unsupported version", func() {
+		BeforeEach(func() {
+			request.Header.Set("X-Turbine-Event-Version", "0.9")
+		})
+
+		It("returns 400 with the supported versions", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+
+			body, err := ioutil.ReadAll(response.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(body)).Should(Equal(event.ErrUnsupportedVersion{Version: "0.9"}.Error()))
+		})
+
+		It("does not subscribe", func() {
+			Ω(scheduler.SubscribeCallCount()).Should(BeZero())
+		})
+	})
+
 	Context("when subscribing fails", func() {
 		BeforeEach(func() {
 			scheduler.SubscribeReturns(nil, nil, errors.New("oh no!"))
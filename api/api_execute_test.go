@@ -24,11 +24,27 @@ var _ = Describe("POST /builds", func() {
 
 	BeforeEach(func() {
 		build = turbine.Build{
+			Config: turbine.Config{
+				Image: "some-image",
+				Run: turbine.RunConfig{
+					Path: "some-script",
+				},
+			},
+
 			Inputs: []turbine.Input{
 				{
+					Name: "some-input",
 					Type: "git",
 				},
 			},
+
+			Outputs: []turbine.Output{
+				{
+					Name: "some-output",
+					Type: "s3",
+					On:   turbine.OutputConditions{turbine.OutputConditionSuccess},
+				},
+			},
 		}
 
 		requestBody = buildPayload(build)
@@ -66,6 +82,53 @@ var _ = Describe("POST /builds", func() {
 		Ω(response.Header.Get("X-Turbine-Endpoint")).Should(Equal("http://some-turbine"))
 	})
 
+	Context("when the build is invalid", func() {
+		BeforeEach(func() {
+			build.Config.Image = ""
+			build.Inputs = append(build.Inputs, turbine.Input{
+				Name: "some-input",
+				Type: "bogus",
+			})
+			build.Outputs[0].Type = "also-bogus"
+
+			requestBody = buildPayload(build)
+		})
+
+		It("returns 422", func() {
+			Ω(response.StatusCode).Should(Equal(422))
+		})
+
+		It("returns every problem with the build", func() {
+			var validationErr turbine.ValidationError
+			err := json.NewDecoder(response.Body).Decode(&validationErr)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(validationErr.Problems).Should(Equal([]string{
+				"no image specified",
+				"input 'some-input' is specified more than once",
+				"input 'some-input' has unknown type 'bogus'",
+				"output 'some-output' has unknown type 'also-bogus'",
+			}))
+		})
+
+		It("does not schedule the build", func() {
+			Ω(scheduler.StartCallCount()).Should(BeZero())
+		})
+	})
+
+	Context("when an input provides the build's config", func() {
+		BeforeEach(func() {
+			build.Config = turbine.Config{}
+			build.Inputs[0].ConfigPath = "build.yml"
+
+			requestBody = buildPayload(build)
+		})
+
+		It("returns 201", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusCreated))
+		})
+	})
+
 	Context("when the payload is malformed JSON", func() {
 		BeforeEach(func() {
 			requestBody = "ß"
@@ -0,0 +1,82 @@
+package api_test
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/concourse/turbine/event"
+	sched "github.com/concourse/turbine/scheduler"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GET /builds/:guid/events.json", func() {
+	var path string
+	var response *http.Response
+
+	BeforeEach(func() {
+		path = "/builds/some-build-guid/events.json"
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		response, err = client.Get(server.URL + path)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when the build is known", func() {
+		BeforeEach(func() {
+			hub := event.NewHub(clock)
+			hub.EmitEvent(event.CURRENT_VERSION)
+			hub.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 1}})
+			hub.EmitEvent(event.Log{Payload: "hello", Timestamp: event.Timestamp{Time: 2}})
+			hub.EmitEvent(event.End{})
+			hub.Close()
+
+			scheduler.LookupReturns(sched.ScheduledBuild{EventHub: hub}, true)
+		})
+
+		It("returns 200 with the events as a downloadable array of messages", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			Ω(response.Header.Get("Content-Type")).Should(Equal("application/json"))
+			Ω(response.Header.Get("Content-Disposition")).Should(Equal(`attachment; filename=some-build-guid-events.json`))
+
+			var messages []event.Message
+			err := json.NewDecoder(response.Body).Decode(&messages)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(messages).Should(Equal([]event.Message{
+				{ID: "0", Event: event.CURRENT_VERSION},
+				{ID: "1", Event: event.Start{Timestamp: event.Timestamp{Time: 1}}},
+				{ID: "2", Event: event.Log{Payload: "hello", Timestamp: event.Timestamp{Time: 2}}},
+				{ID: "3", Event: event.End{}},
+			}))
+		})
+
+		Context("when the guid has characters special to the header", func() {
+			BeforeEach(func() {
+				path = `/builds/some%22build;%20guid/events.json`
+			})
+
+			It("quotes it in the filename", func() {
+				disposition, params, err := mime.ParseMediaType(response.Header.Get("Content-Disposition"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(disposition).Should(Equal("attachment"))
+				Ω(params).Should(Equal(map[string]string{"filename": `some"build; guid-events.json`}))
+			})
+		})
+	})
+
+	Context("when the build is unknown", func() {
+		BeforeEach(func() {
+			scheduler.LookupReturns(sched.ScheduledBuild{}, false)
+		})
+
+		It("returns 404", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+})
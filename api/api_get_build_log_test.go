@@ -0,0 +1,100 @@
+package api_test
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/concourse/turbine/event"
+	sched "github.com/concourse/turbine/scheduler"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GET /builds/:guid/log", func() {
+	var query string
+
+	var response *http.Response
+
+	BeforeEach(func() {
+		query = ""
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		response, err = client.Get(server.URL + "/builds/some-build-guid/log" + query)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	body := func() string {
+		body, err := ioutil.ReadAll(response.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(body)
+	}
+
+	Context("when the build is known", func() {
+		BeforeEach(func() {
+			hub := event.NewHub(clock)
+			hub.EmitEvent(event.CURRENT_VERSION)
+			hub.EmitEvent(event.Log{Origin: event.Origin{Type: event.OriginTypeInput, Name: "repo"}, Payload: "cloning\n"})
+			hub.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 1}})
+			hub.EmitEvent(event.Log{Origin: event.Origin{Type: event.OriginTypeRun, Name: "stdout"}, Payload: "\x1b[32mok\x1b[0m\n"})
+			hub.EmitEvent(event.Log{Origin: event.Origin{Type: event.OriginTypeOutput, Name: "tag"}, Payload: "pushing\n"})
+
+			scheduler.LookupReturns(sched.ScheduledBuild{EventHub: hub}, true)
+		})
+
+		It("looks up the build via the scheduler", func() {
+			Ω(scheduler.LookupCallCount()).Should(Equal(1))
+			Ω(scheduler.LookupArgsForCall(0)).Should(Equal("some-build-guid"))
+		})
+
+		It("returns 200 with the concatenated logs as plain text", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			Ω(response.Header.Get("Content-Type")).Should(Equal("text/plain; charset=utf-8"))
+
+			Ω(body()).Should(Equal("cloning\n\x1b[32mok\x1b[0m\npushing\n"))
+		})
+
+		Context("when filtering by origin", func() {
+			BeforeEach(func() {
+				query = "?origin=run&origin=input:repo"
+			})
+
+			It("returns only logs from the origins", func() {
+				Ω(body()).Should(Equal("cloning\n\x1b[32mok\x1b[0m\n"))
+			})
+		})
+
+		Context("with an unknown origin type", func() {
+			BeforeEach(func() {
+				query = "?origin=bogus"
+			})
+
+			It("returns 400", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("when stripping ANSI escape sequences", func() {
+			BeforeEach(func() {
+				query = "?strip_ansi=true&origin=run"
+			})
+
+			It("removes them from the logs", func() {
+				Ω(body()).Should(Equal("ok\n"))
+			})
+		})
+	})
+
+	Context("when the build is unknown", func() {
+		BeforeEach(func() {
+			scheduler.LookupReturns(sched.ScheduledBuild{}, false)
+		})
+
+		It("returns 404", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+})
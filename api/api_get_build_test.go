@@ -0,0 +1,84 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/concourse/turbine"
+	sched "github.com/concourse/turbine/scheduler"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GET /builds/:guid", func() {
+	var response *http.Response
+
+	JustBeforeEach(func() {
+		var err error
+
+		response, err = client.Get(server.URL + "/builds/some-build-guid")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when the build is known", func() {
+		var build turbine.Build
+
+		BeforeEach(func() {
+			build = turbine.Build{
+				Guid: "some-build-guid",
+				Config: turbine.Config{
+					Image:        "some-image",
+					SecretParams: map[string]string{"TOKEN": "s3cr3t"},
+				},
+				Inputs: []turbine.Input{
+					{Name: "some-input", Version: turbine.Version{"ref": "abc"}},
+				},
+				Outputs: []turbine.Output{
+					{Name: "some-output", Version: turbine.Version{"ref": "def"}},
+				},
+			}
+
+			scheduler.LookupReturns(sched.ScheduledBuild{
+				Build:  build,
+				Status: turbine.StatusSucceeded,
+			}, true)
+		})
+
+		It("looks up the build via the scheduler", func() {
+			Ω(scheduler.LookupCallCount()).Should(Equal(1))
+			Ω(scheduler.LookupArgsForCall(0)).Should(Equal("some-build-guid"))
+		})
+
+		It("returns 200 with the build as last computed, and its status", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			Ω(response.Header.Get("Content-Type")).Should(Equal("application/json"))
+
+			var state turbine.BuildState
+			err := json.NewDecoder(response.Body).Decode(&state)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(state.Status).Should(Equal(turbine.StatusSucceeded))
+			Ω(state.Build.Inputs).Should(Equal(build.Inputs))
+			Ω(state.Build.Outputs).Should(Equal(build.Outputs))
+		})
+
+		It("does not reveal secret params", func() {
+			body, err := ioutil.ReadAll(response.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(body)).ShouldNot(ContainSubstring("s3cr3t"))
+		})
+	})
+
+	Context("when the build is unknown", func() {
+		BeforeEach(func() {
+			scheduler.LookupReturns(sched.ScheduledBuild{}, false)
+		})
+
+		It("returns 404", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -10,9 +10,9 @@ import (
 	"net/http"
 	"net/http/httputil"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
-	gfakes "github.com/cloudfoundry-incubator/garden/api/fakes"
 	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/runtime"
+	rtfakes "github.com/concourse/turbine/runtime/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -28,7 +28,7 @@ var _ = Describe("POST /builds/:guid/hijack", func() {
 	BeforeEach(func() {
 		var err error
 
-		payload, err = json.Marshal(garden.ProcessSpec{
+		payload, err = json.Marshal(runtime.ProcessSpec{
 			Path: "bash",
 			Args: []string{"-l"},
 		})
@@ -59,10 +59,10 @@ var _ = Describe("POST /builds/:guid/hijack", func() {
 	})
 
 	Context("when hijacking succeeds", func() {
-		var process *gfakes.FakeProcess
+		var process *rtfakes.FakeProcess
 
 		BeforeEach(func() {
-			process = new(gfakes.FakeProcess)
+			process = new(rtfakes.FakeProcess)
 
 			scheduler.HijackReturns(process, nil)
 		})
@@ -72,7 +72,7 @@ var _ = Describe("POST /builds/:guid/hijack", func() {
 
 			guid, spec, _ := scheduler.HijackArgsForCall(0)
 			Ω(guid).Should(Equal("some-build-guid"))
-			Ω(spec).Should(Equal(garden.ProcessSpec{
+			Ω(spec).Should(Equal(runtime.ProcessSpec{
 				Path: "bash",
 				Args: []string{"-l"},
 			}))
@@ -84,7 +84,7 @@ var _ = Describe("POST /builds/:guid/hijack", func() {
 
 		Context("when the process prints stdout and stderr", func() {
 			BeforeEach(func() {
-				scheduler.HijackStub = func(guid string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+				scheduler.HijackStub = func(guid string, spec runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
 					Ω(io.Stdout).ShouldNot(BeZero())
 					Ω(io.Stderr).ShouldNot(BeZero())
 
@@ -94,7 +94,7 @@ var _ = Describe("POST /builds/:guid/hijack", func() {
 					_, err = fmt.Fprintf(io.Stderr, "hello client err\n")
 					Ω(err).ShouldNot(HaveOccurred())
 
-					return new(gfakes.FakeProcess), nil
+					return new(rtfakes.FakeProcess), nil
 				}
 			})
 
@@ -140,8 +140,8 @@ var _ = Describe("POST /builds/:guid/hijack", func() {
 			})
 
 			It("forwards tty spec paylods to the process", func() {
-				ttySpec := &garden.TTYSpec{
-					WindowSize: &garden.WindowSize{
+				ttySpec := &runtime.TTYSpec{
+					WindowSize: &runtime.WindowSize{
 						Columns: 80,
 						Rows:    24,
 					},
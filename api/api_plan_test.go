@@ -0,0 +1,260 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/concourse/turbine"
+	rfakes "github.com/concourse/turbine/resource/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("POST /builds/plan", func() {
+	var build turbine.Build
+	var response *http.Response
+
+	BeforeEach(func() {
+		build = turbine.Build{
+			Config: turbine.Config{
+				Image: "some-image",
+				Params: map[string]string{
+					"FOO": "bar",
+				},
+				SecretParams: map[string]string{
+					"TOKEN": "s3cr3t",
+				},
+				Run: turbine.RunConfig{
+					Path: "some-script",
+				},
+				Inputs: []turbine.InputConfig{
+					{Name: "some-input", Path: "some/path"},
+				},
+			},
+
+			Inputs: []turbine.Input{
+				{
+					Name:     "some-input",
+					Resource: "some-resource",
+					Type:     "git",
+				},
+				{
+					Name:     "another-input",
+					Resource: "another-resource",
+					Type:     "s3",
+				},
+			},
+
+			Outputs: []turbine.Output{
+				{
+					Name: "some-output",
+					Type: "s3",
+					On:   turbine.OutputConditions{turbine.OutputConditionSuccess},
+				},
+				{
+					Name: "another-output",
+					Type: "git",
+					On: turbine.OutputConditions{
+						turbine.OutputConditionSuccess,
+						turbine.OutputConditionFailure,
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		payload, err := json.Marshal(build)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		response, err = client.Post(
+			server.URL+"/builds/plan",
+			"application/json",
+			bytes.NewBuffer(payload),
+		)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("returns 200", func() {
+		Ω(response.StatusCode).Should(Equal(http.StatusOK))
+	})
+
+	It("returns the plan without scheduling the build", func() {
+		var plan turbine.BuildPlan
+		err := json.NewDecoder(response.Body).Decode(&plan)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(plan).Should(Equal(turbine.BuildPlan{
+			Config: build.Config.Redacted(),
+			Inputs: []turbine.InputPlan{
+				{
+					Name:        "some-input",
+					Resource:    "some-resource",
+					Type:        "git",
+					Destination: "/tmp/build/src/some/path",
+				},
+				{
+					Name:        "another-input",
+					Resource:    "another-resource",
+					Type:        "s3",
+					Destination: "/tmp/build/src/another-input",
+				},
+			},
+			Outputs: turbine.OutputsPlan{
+				OnSuccess: []string{"some-output", "another-output"},
+				OnFailure: []string{"another-output"},
+				OnAborted: []string{},
+				OnErrored: []string{},
+			},
+		}))
+
+		Ω(scheduler.StartCallCount()).Should(BeZero())
+		Ω(tracker.InitCallCount()).Should(BeZero())
+	})
+
+	It("does not reveal secret params", func() {
+		body, err := ioutil.ReadAll(response.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(string(body)).ShouldNot(ContainSubstring("s3cr3t"))
+	})
+
+	Context("when an input provides config", func() {
+		var resource *rfakes.FakeResource
+
+		BeforeEach(func() {
+			build.Config = turbine.Config{
+				Params: map[string]string{
+					"FOO": "bar",
+				},
+			}
+
+			build.Inputs[0].ConfigPath = "build.yml"
+
+			resource = new(rfakes.FakeResource)
+			tracker.InitReturns(resource, nil)
+		})
+
+		Context("and fetching it succeeds", func() {
+			BeforeEach(func() {
+				resource.FetchConfigStub = func(input turbine.Input) (turbine.Input, turbine.Config, error) {
+					input.Version = turbine.Version{"ref": "abc123"}
+
+					return input, turbine.Config{
+						Image: "input-image",
+						Params: map[string]string{
+							"FOO": "input-foo",
+							"BAZ": "input-baz",
+						},
+						Run: turbine.RunConfig{
+							Path: "input-script",
+						},
+					}, nil
+				}
+			})
+
+			It("fetches only the config of that input", func() {
+				Ω(tracker.InitCallCount()).Should(Equal(1))
+
+				typ, _, _, _ := tracker.InitArgsForCall(0)
+				Ω(typ).Should(Equal("git"))
+
+				Ω(resource.FetchConfigCallCount()).Should(Equal(1))
+				Ω(resource.FetchConfigArgsForCall(0)).Should(Equal(build.Inputs[0]))
+				Ω(resource.InCallCount()).Should(BeZero())
+
+				Ω(tracker.ReleaseCallCount()).Should(Equal(1))
+				Ω(tracker.ReleaseArgsForCall(0)).Should(Equal(resource))
+			})
+
+			It("plans with the merged config and the fetched version", func() {
+				var plan turbine.BuildPlan
+				err := json.NewDecoder(response.Body).Decode(&plan)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(plan.Config).Should(Equal(turbine.Config{
+					Image: "input-image",
+					Params: map[string]string{
+						"FOO": "bar",
+						"BAZ": "input-baz",
+					},
+					Run: turbine.RunConfig{
+						Path: "input-script",
+					},
+				}))
+
+				Ω(plan.Inputs[0].Version).Should(Equal(turbine.Version{"ref": "abc123"}))
+				Ω(plan.Inputs[1].Version).Should(BeNil())
+			})
+		})
+
+		Context("and the merged config is incomplete", func() {
+			BeforeEach(func() {
+				resource.FetchConfigReturns(build.Inputs[0], turbine.Config{}, nil)
+			})
+
+			It("returns 422 with the problems", func() {
+				Ω(response.StatusCode).Should(Equal(422))
+
+				var validationErr turbine.ValidationError
+				err := json.NewDecoder(response.Body).Decode(&validationErr)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(validationErr.Problems).Should(Equal([]string{
+					"no image specified",
+					"no run path specified",
+				}))
+			})
+		})
+
+		Context("and fetching it fails", func() {
+			BeforeEach(func() {
+				resource.FetchConfigReturns(turbine.Input{}, turbine.Config{}, errors.New("oh no!"))
+			})
+
+			It("returns 500", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+
+			It("releases the resource", func() {
+				Ω(tracker.ReleaseCallCount()).Should(Equal(1))
+			})
+		})
+	})
+
+	Context("when the build is invalid", func() {
+		BeforeEach(func() {
+			build.Config.Image = ""
+			build.Inputs[1].Type = "bogus"
+		})
+
+		It("returns 422 with the problems", func() {
+			Ω(response.StatusCode).Should(Equal(422))
+
+			var validationErr turbine.ValidationError
+			err := json.NewDecoder(response.Body).Decode(&validationErr)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(validationErr.Problems).Should(Equal([]string{
+				"no image specified",
+				"input 'another-input' has unknown type 'bogus'",
+			}))
+		})
+	})
+
+	Context("when the payload is malformed", func() {
+		It("returns 400", func() {
+			response, err := client.Post(
+				server.URL+"/builds/plan",
+				"application/json",
+				bytes.NewBufferString("ß"),
+			)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+		})
+	})
+})
@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/concourse/turbine/api"
+	"github.com/concourse/turbine/config"
 	rfakes "github.com/concourse/turbine/resource/fakes"
 	sfakes "github.com/concourse/turbine/scheduler/fakes"
 	. "github.com/onsi/ginkgo"
@@ -16,6 +18,7 @@ import (
 var scheduler *sfakes.FakeScheduler
 var tracker *rfakes.FakeTracker
 var drain chan struct{}
+var clock *sfakes.FakeClock
 
 var server *httptest.Server
 var client *http.Client
@@ -24,11 +27,19 @@ var _ = BeforeEach(func() {
 	scheduler = new(sfakes.FakeScheduler)
 	tracker = new(rfakes.FakeTracker)
 	drain = make(chan struct{})
+	clock = new(sfakes.FakeClock)
 
 	handler, err := api.New(
 		lagertest.NewTestLogger("test"),
 		scheduler,
 		tracker,
+		config.ResourceTypes{
+			{Name: "git", Image: "some-git-image"},
+			{Name: "s3", Image: "some-s3-image"},
+		},
+		0,
+		time.Minute,
+		clock,
 		"http://some-turbine",
 		drain,
 	)
@@ -0,0 +1,187 @@
+package api_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/resource/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GET /checks/watch", func() {
+	var (
+		request turbine.WatchRequest
+
+		resource *fakes.FakeResource
+		ticks    chan time.Time
+
+		conn *websocket.Conn
+	)
+
+	BeforeEach(func() {
+		request = turbine.WatchRequest{
+			Input: turbine.Input{
+				Type:   "git",
+				Source: turbine.Source{"uri": "example.com"},
+			},
+			Interval: turbine.Duration(time.Minute),
+		}
+
+		resource = new(fakes.FakeResource)
+		tracker.InitForCheckReturns(resource, nil)
+
+		// watchers from earlier specs may still be winding down
+		specTicks := make(chan time.Time)
+		clock.AfterStub = func(time.Duration) <-chan time.Time {
+			return specTicks
+		}
+
+		ticks = specTicks
+
+		var err error
+
+		dialer := &websocket.Dialer{}
+
+		conn, _, err = dialer.Dial("ws://"+server.Listener.Addr().String()+"/checks/watch", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		conn.Close()
+	})
+
+	JustBeforeEach(func() {
+		err := conn.WriteJSON(request)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	readVersions := func() []turbine.Version {
+		var versions []turbine.Version
+		err := conn.ReadJSON(&versions)
+		Ω(err).ShouldNot(HaveOccurred())
+		return versions
+	}
+
+	Context("when the resource yields versions", func() {
+		BeforeEach(func() {
+			// like most resources, includes the version it checked from
+			resource.CheckStub = func(input turbine.Input) ([]turbine.Version, error) {
+				switch input.Version["ref"] {
+				case nil:
+					return []turbine.Version{{"ref": "a"}}, nil
+				case "a":
+					return []turbine.Version{{"ref": "a"}, {"ref": "b"}, {"ref": "c"}}, nil
+				default:
+					return []turbine.Version{input.Version}, nil
+				}
+			}
+		})
+
+		It("pushes the versions it finds, checking on the interval", func() {
+			Ω(readVersions()).Should(Equal([]turbine.Version{{"ref": "a"}}))
+
+			Eventually(clock.AfterCallCount).Should(Equal(1))
+			Ω(clock.AfterArgsForCall(0)).Should(Equal(time.Minute))
+		})
+
+		It("pushes only new versions, checking from the last one pushed", func() {
+			Ω(readVersions()).Should(Equal([]turbine.Version{{"ref": "a"}}))
+
+			ticks <- time.Now()
+
+			Ω(readVersions()).Should(Equal([]turbine.Version{{"ref": "b"}, {"ref": "c"}}))
+			Ω(resource.CheckArgsForCall(1).Version).Should(Equal(turbine.Version{"ref": "a"}))
+
+			ticks <- time.Now()
+
+			Eventually(resource.CheckCallCount).Should(Equal(3))
+			Ω(resource.CheckArgsForCall(2).Version).Should(Equal(turbine.Version{"ref": "c"}))
+		})
+	})
+
+	Context("when checking keeps failing", func() {
+		BeforeEach(func() {
+			resource.CheckReturns(nil, errors.New("oh no!"))
+		})
+
+		It("backs off exponentially", func() {
+			Eventually(clock.AfterCallCount).Should(Equal(1))
+			ticks <- time.Now()
+
+			Eventually(clock.AfterCallCount).Should(Equal(2))
+			ticks <- time.Now()
+
+			Eventually(clock.AfterCallCount).Should(Equal(3))
+
+			Ω(clock.AfterArgsForCall(0)).Should(Equal(time.Minute))
+			Ω(clock.AfterArgsForCall(1)).Should(Equal(2 * time.Minute))
+			Ω(clock.AfterArgsForCall(2)).Should(Equal(4 * time.Minute))
+		})
+
+		Context("with an interval longer than the maximum backoff", func() {
+			BeforeEach(func() {
+				request.Interval = turbine.Duration(time.Hour)
+			})
+
+			It("does not check more often than the interval", func() {
+				Eventually(clock.AfterCallCount).Should(Equal(1))
+				ticks <- time.Now()
+
+				Eventually(clock.AfterCallCount).Should(Equal(2))
+
+				Ω(clock.AfterArgsForCall(0)).Should(Equal(time.Hour))
+				Ω(clock.AfterArgsForCall(1)).Should(Equal(time.Hour))
+			})
+		})
+	})
+
+	Context("when the client goes away", func() {
+		BeforeEach(func() {
+			resource.CheckReturns([]turbine.Version{}, nil)
+		})
+
+		It("stops checking", func() {
+			Eventually(clock.AfterCallCount).Should(Equal(1))
+
+			err := conn.Close()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// once it notices, nothing waits for the next tick
+			Eventually(func() bool {
+				select {
+				case ticks <- time.Now():
+					return false
+				case <-time.After(50 * time.Millisecond):
+					return true
+				}
+			}).Should(BeTrue())
+
+			checks := resource.CheckCallCount()
+			Consistently(resource.CheckCallCount).Should(Equal(checks))
+		})
+	})
+
+	Context("when the interval is too short", func() {
+		BeforeEach(func() {
+			request.Interval = turbine.Duration(time.Nanosecond)
+		})
+
+		It("closes the connection with an error", func() {
+			var checkErr turbine.CheckError
+			err := conn.ReadJSON(&checkErr)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(checkErr.Code).Should(Equal(turbine.CheckErrorInvalidRequest))
+
+			Ω(checkErr.Message).Should(Equal("watch interval must be at least 1s"))
+
+			_, _, err = conn.ReadMessage()
+			Ω(websocket.IsCloseError(err, websocket.CloseInternalServerErr)).Should(BeTrue())
+
+			Ω(resource.CheckCallCount()).Should(BeZero())
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package check
+
+import (
+	"net/http"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/resource"
+)
+
+// checkError describes why a check failed, along with the HTTP status to
+// respond with.
+func checkError(err error) (int, turbine.CheckError) {
+	checkErr := turbine.CheckError{
+		Code:    turbine.CheckErrorInternal,
+		Message: err.Error(),
+	}
+
+	status := http.StatusInternalServerError
+
+	switch failure := err.(type) {
+	case resource.ErrCreatingContainer:
+		checkErr.Code = turbine.CheckErrorContainerCreationFailed
+
+	case resource.ErrResourceScriptFailed:
+		status = http.StatusBadGateway
+		checkErr.Code = turbine.CheckErrorScriptFailed
+		checkErr.ExitStatus = failure.ExitStatus
+		checkErr.Stdout = failure.Stdout
+		checkErr.Stderr = failure.Stderr
+
+	case resource.ErrResourceScriptMalformedOutput:
+		status = http.StatusBadGateway
+		checkErr.Code = turbine.CheckErrorMalformedOutput
+		checkErr.Stdout = failure.Stdout
+
+	case resource.ErrResourceScriptTimedOut:
+		status = http.StatusGatewayTimeout
+		checkErr.Code = turbine.CheckErrorScriptTimedOut
+
+	default:
+		switch err {
+		case ErrInvalidWatchInterval:
+			status = http.StatusBadRequest
+			checkErr.Code = turbine.CheckErrorInvalidRequest
+
+		case resource.ErrUnknownResourceType:
+			status = http.StatusBadRequest
+			checkErr.Code = turbine.CheckErrorUnknownResourceType
+
+		case resource.ErrAborted:
+			status = http.StatusServiceUnavailable
+			checkErr.Code = turbine.CheckErrorAborted
+		}
+	}
+
+	return status, checkErr
+}
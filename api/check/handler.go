@@ -2,7 +2,6 @@ package check
 
 import (
 	"encoding/json"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/pivotal-golang/lager"
@@ -15,13 +14,23 @@ type Handler struct {
 	logger lager.Logger
 
 	tracker resource.Tracker
+	checker resource.Checker
+	clock   resource.Clock
 	drain   <-chan struct{}
 }
 
-func NewHandler(logger lager.Logger, tracker resource.Tracker, drain <-chan struct{}) *Handler {
+func NewHandler(
+	logger lager.Logger,
+	tracker resource.Tracker,
+	checker resource.Checker,
+	clock resource.Clock,
+	drain <-chan struct{},
+) *Handler {
 	return &Handler{
 		logger:  logger,
 		tracker: tracker,
+		checker: checker,
+		clock:   clock,
 		drain:   drain,
 	}
 }
@@ -45,21 +54,16 @@ func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"from": input.Version,
 	})
 
-	resource, err := handler.tracker.Init(input.Type, ioutil.Discard, nil)
+	// identical concurrent checks are run once and share the result
+	versions, err := handler.checker.Check(input)
 	if err != nil {
-		log.Error("failed-to-init", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		return
-	}
+		log.Error("failed-to-check", err)
 
-	defer handler.tracker.Release(resource)
+		status, checkErr := checkError(err)
 
-	versions, err := resource.Check(input)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Error("failed-to-check", err)
-		w.Write([]byte(err.Error()))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(checkErr)
 		return
 	}
 
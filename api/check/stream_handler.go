@@ -2,8 +2,8 @@ package check
 
 import (
 	"io"
-	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pivotal-golang/lager"
@@ -49,9 +49,10 @@ func (handler *Handler) Stream(w http.ResponseWriter, r *http.Request) {
 		"type":     input.Type,
 	})
 
-	resource, err := handler.tracker.Init(input.Type, ioutil.Discard, handler.drain)
+	resource, err := handler.tracker.InitForCheck(input.Type, input.Source, handler.drain)
 	if err != nil {
 		log.Error("failed-to-init", err)
+		closeWithError(log, conn, err)
 		return
 	}
 
@@ -65,6 +66,7 @@ func (handler *Handler) Stream(w http.ResponseWriter, r *http.Request) {
 		versions, err := resource.Check(input)
 		if err != nil {
 			log.Error("failed-to-check", err)
+			closeWithError(log, conn, err)
 			return
 		}
 
@@ -90,3 +92,33 @@ func (handler *Handler) Stream(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// the most a close frame's reason may hold: a control frame's 125 byte
+// payload, less the 2 byte close code
+const maxCloseReasonLength = 123
+
+// closeWithError sends the client a turbine.CheckError describing why the
+// check failed, and then closes the connection. The close reason is only the
+// error's code; the full error has already been sent.
+func closeWithError(logger lager.Logger, conn *websocket.Conn, err error) {
+	_, checkErr := checkError(err)
+
+	writeErr := conn.WriteJSON(checkErr)
+	if writeErr != nil {
+		logger.Error("failed-to-send-error", writeErr)
+	}
+
+	reason := string(checkErr.Code)
+	if len(reason) > maxCloseReasonLength {
+		reason = reason[:maxCloseReasonLength]
+	}
+
+	writeErr = conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseInternalServerErr, reason),
+		time.Now().Add(time.Second),
+	)
+	if writeErr != nil {
+		logger.Error("failed-to-close", writeErr)
+	}
+}
@@ -0,0 +1,145 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/turbine"
+)
+
+// the longest to wait between checks that keep failing, unless the interval
+// is longer
+var MaxWatchBackoff = 5 * time.Minute
+
+// the shortest interval a watch may check on
+var MinWatchInterval = time.Second
+
+var ErrInvalidWatchInterval = fmt.Errorf("watch interval must be at least %s", MinWatchInterval)
+
+// Watch checks for new versions on the subscriber's interval, pushing each
+// batch of newly discovered versions until the connection closes. The last
+// version pushed is the cursor for the next check.
+func (handler *Handler) Watch(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		handler.logger.Error("upgrade-failed", err)
+		return
+	}
+
+	defer conn.Close()
+
+	var request turbine.WatchRequest
+	err = conn.ReadJSON(&request)
+	if err != nil {
+		handler.logger.Error("malformed-request", err)
+		return
+	}
+
+	if time.Duration(request.Interval) < MinWatchInterval {
+		closeWithError(handler.logger, conn, ErrInvalidWatchInterval)
+		return
+	}
+
+	log := handler.logger.Session("watch", lager.Data{
+		"resource": request.Input.Resource,
+		"type":     request.Input.Type,
+		"interval": time.Duration(request.Interval).String(),
+	})
+
+	// nothing more is expected from the client, but reading is how we notice
+	// it going away
+	disconnected := make(chan struct{})
+	go func() {
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				close(disconnected)
+				return
+			}
+		}
+	}()
+
+	input := request.Input
+	interval := time.Duration(request.Interval)
+
+	var backoff time.Duration
+
+	for {
+		log.Debug("checking", lager.Data{
+			"from": input.Version,
+		})
+
+		wait := interval
+
+		versions, err := handler.checker.Check(input)
+		if err != nil {
+			backoff = nextBackoff(backoff, interval)
+			wait = backoff
+
+			log.Error("failed-to-check", err, lager.Data{
+				"retrying-in": backoff.String(),
+			})
+		} else {
+			backoff = 0
+
+			discovered := newVersions(versions, input.Version)
+			if len(discovered) > 0 {
+				err := conn.WriteJSON(discovered)
+				if err != nil {
+					log.Error("failed-to-push", err)
+					return
+				}
+
+				input.Version = discovered[len(discovered)-1]
+			}
+		}
+
+		select {
+		case <-handler.clock.After(wait):
+		case <-disconnected:
+			return
+		case <-handler.drain:
+			return
+		}
+	}
+}
+
+// nextBackoff doubles the previous backoff, starting from the interval and
+// capped at MaxWatchBackoff, or the interval if it is longer
+func nextBackoff(previous time.Duration, interval time.Duration) time.Duration {
+	if previous == 0 {
+		previous = interval
+	} else {
+		previous *= 2
+	}
+
+	limit := MaxWatchBackoff
+	if interval > limit {
+		limit = interval
+	}
+
+	if previous > limit {
+		previous = limit
+	}
+
+	return previous
+}
+
+// check may include the version it checked from; it is not new
+func newVersions(versions []turbine.Version, cursor turbine.Version) []turbine.Version {
+	discovered := []turbine.Version{}
+
+	for _, version := range versions {
+		if cursor != nil && reflect.DeepEqual(version, cursor) {
+			continue
+		}
+
+		discovered = append(discovered, version)
+	}
+
+	return discovered
+}
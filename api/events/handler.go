@@ -4,48 +4,58 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/concourse/turbine/event"
 	"github.com/concourse/turbine/scheduler"
 	"github.com/pivotal-golang/lager"
 	"github.com/vito/go-sse/sse"
 )
 
-type handler struct {
+type Handler struct {
 	logger    lager.Logger
 	scheduler scheduler.Scheduler
+
+	clock             scheduler.Clock
+	keepaliveInterval time.Duration
 }
 
-func NewHandler(logger lager.Logger, scheduler scheduler.Scheduler) http.Handler {
-	return &handler{
+// NewHandler returns a handler for streaming build events. Idle streams are
+// sent a keepalive every keepaliveInterval, so that proxies don't drop them;
+// 0 disables keepalives.
+func NewHandler(
+	logger lager.Logger,
+	scheduler scheduler.Scheduler,
+	clock scheduler.Clock,
+	keepaliveInterval time.Duration,
+) *Handler {
+	return &Handler{
 		logger:    logger,
 		scheduler: scheduler,
+
+		clock:             clock,
+		keepaliveInterval: keepaliveInterval,
 	}
 }
 
-func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	guid := r.FormValue(":guid")
 
 	hLog := handler.logger.Session("build-events", lager.Data{
 		"guid": guid,
 	})
 
-	var idx uint = 0
-
-	lastID := r.Header.Get("Last-Event-ID")
-	if lastID != "" {
-		_, err := fmt.Sscanf(lastID, "%d", &idx)
-		if err != nil {
-			hLog.Error("bad-id", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
-		idx++
+	sub, err := parseSubscription(r)
+	if err != nil {
+		hLog.Error("bad-request", err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
 	}
 
-	hLog.Info("subscribe", lager.Data{"last-event-id": idx})
+	hLog.Info("subscribe", lager.Data{"last-event-id": sub.from, "version": sub.version})
 
-	events, stop, err := handler.scheduler.Subscribe(guid, idx)
+	events, stop, err := handler.scheduler.Subscribe(guid, sub.from)
 	if err != nil {
 		hLog.Error("failed-to-subscribe", err)
 		w.WriteHeader(http.StatusNotFound)
@@ -60,41 +70,174 @@ func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "text/event-stream; charset=utf-8")
 	w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Add("Connection", "keep-alive")
+	w.Header().Add("X-Turbine-Event-Version", string(sub.version))
 
 	w.WriteHeader(http.StatusOK)
 
 	flusher.Flush()
 
-	for {
-		sseEvent := sse.Event{
-			ID: fmt.Sprintf("%d", idx),
-		}
+	keepalive := handler.keepalive()
 
+	idx := sub.from
+
+	for {
 		select {
 		case e, ok := <-events:
 			if !ok {
 				return
 			}
 
+			id := idx
+
+			// skipped events still take up an id, so that ids are the same
+			// regardless of filter or version for resuming via Last-Event-ID
+			idx++
+
+			e, ok = sub.prepare(e)
+			if !ok {
+				continue
+			}
+
 			data, err := json.Marshal(e)
 			if err != nil {
 				return
 			}
 
-			sseEvent.Name = string(e.EventType())
-			sseEvent.Data = data
+			err = sse.Event{
+				ID:   fmt.Sprintf("%d", id),
+				Name: string(e.EventType()),
+				Data: data,
+			}.Write(w)
+			if err != nil {
+				return
+			}
+
+			// the stream isn't idle; wait a full interval from here
+			keepalive = handler.keepalive()
+
+		case <-keepalive:
+			// comments are ignored by clients
+			_, err := fmt.Fprintf(w, ": keepalive\n\n")
+			if err != nil {
+				return
+			}
+
+			keepalive = handler.keepalive()
 
 		case <-closed:
 			return
 		}
 
-		err = sseEvent.Write(w)
+		flusher.Flush()
+	}
+}
+
+// keepalive returns a channel that fires when the next keepalive is due
+func (handler *Handler) keepalive() <-chan time.Time {
+	if handler.keepaliveInterval == 0 {
+		return nil
+	}
+
+	return handler.clock.After(handler.keepaliveInterval)
+}
+
+// what a client asked to be sent from a build's events
+type subscription struct {
+	// id of the first event to send
+	from uint
+
+	filter  event.Filter
+	version event.Version
+}
+
+// parseSubscription reads the id of the last event the client received from
+// the Last-Event-ID header or ?last_event_id=, along with the events it wants
+// and the version it understands.
+func parseSubscription(r *http.Request) (subscription, error) {
+	var sub subscription
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+
+	if lastID != "" {
+		_, err := fmt.Sscanf(lastID, "%d", &sub.from)
 		if err != nil {
-			return
+			return subscription{}, fmt.Errorf("invalid last event id: %q", lastID)
 		}
 
-		flusher.Flush()
+		sub.from++
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return subscription{}, err
+	}
+
+	sub.filter = filter
+
+	sub.version = requestedVersion(r)
+
+	err = sub.version.Validate()
+	if err != nil {
+		return subscription{}, err
+	}
+
+	return sub, nil
+}
 
-		idx++
+// prepare returns the event as it should be sent, or false if it should be
+// skipped
+func (sub subscription) prepare(e event.Event) (event.Event, bool) {
+	if !sub.filter.Matches(e) {
+		return nil, false
 	}
+
+	// events not present in the requested version are skipped too
+	return sub.version.Translate(e)
+}
+
+// requestedVersion reads the event stream version the client understands,
+// e.g. ?version=1.1, or from the X-Turbine-Event-Version header. Clients that
+// don't ask get the current version.
+func requestedVersion(r *http.Request) event.Version {
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		version = r.Header.Get("X-Turbine-Event-Version")
+	}
+
+	if version == "" {
+		return event.CURRENT_VERSION
+	}
+
+	return event.Version(version)
+}
+
+// parseFilter reads the event types and origins to send from the query, e.g.
+// ?type=log&origin=run or ?origin=input:repo
+func parseFilter(r *http.Request) (event.Filter, error) {
+	var filter event.Filter
+
+	query := r.URL.Query()
+
+	for _, t := range query["type"] {
+		typ, err := event.ParseEventType(t)
+		if err != nil {
+			return event.Filter{}, err
+		}
+
+		filter.Types = append(filter.Types, typ)
+	}
+
+	for _, o := range query["origin"] {
+		origin, err := event.ParseOrigin(o)
+		if err != nil {
+			return event.Filter{}, err
+		}
+
+		filter.Origins = append(filter.Origins, origin)
+	}
+
+	return filter, nil
 }
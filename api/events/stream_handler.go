@@ -0,0 +1,125 @@
+package events
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/turbine/event"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool {
+		return true
+	},
+}
+
+// Stream sends a build's events over a websocket, as event.Messages with
+// their ids. It understands the same parameters as ServeHTTP; as browsers
+// can't set headers on websockets, clients resume via ?last_event_id=.
+func (handler *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	guid := r.FormValue(":guid")
+
+	hLog := handler.logger.Session("build-events-stream", lager.Data{
+		"guid": guid,
+	})
+
+	sub, err := parseSubscription(r)
+	if err != nil {
+		hLog.Error("bad-request", err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	hLog.Info("subscribe", lager.Data{"last-event-id": sub.from, "version": sub.version})
+
+	events, stop, err := handler.scheduler.Subscribe(guid, sub.from)
+	if err != nil {
+		hLog.Error("failed-to-subscribe", err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	defer close(stop)
+
+	conn, err := upgrader.Upgrade(w, r, http.Header{
+		"X-Turbine-Event-Version": {string(sub.version)},
+	})
+	if err != nil {
+		hLog.Error("upgrade-failed", err)
+		return
+	}
+
+	defer conn.Close()
+
+	closed := make(chan struct{})
+
+	go func() {
+		// nothing is expected from the client, but reading handles pongs
+		// and notices when it goes away
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				close(closed)
+				return
+			}
+		}
+	}()
+
+	keepalive := handler.keepalive()
+
+	idx := sub.from
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					time.Now().Add(time.Second),
+				)
+
+				return
+			}
+
+			id := idx
+
+			// skipped events still take up an id, as with ServeHTTP
+			idx++
+
+			e, ok = sub.prepare(e)
+			if !ok {
+				continue
+			}
+
+			err := conn.WriteJSON(event.Message{
+				ID:    fmt.Sprintf("%d", id),
+				Event: e,
+			})
+			if err != nil {
+				hLog.Error("failed-to-write", err)
+				return
+			}
+
+			// the stream isn't idle; wait a full interval from here
+			keepalive = handler.keepalive()
+
+		case <-keepalive:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second))
+			if err != nil {
+				hLog.Error("failed-to-ping", err)
+				return
+			}
+
+			keepalive = handler.keepalive()
+
+		case <-closed:
+			return
+		}
+	}
+}
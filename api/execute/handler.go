@@ -8,6 +8,7 @@ import (
 	"github.com/pivotal-golang/lager"
 
 	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/config"
 	"github.com/concourse/turbine/scheduler"
 )
 
@@ -15,18 +16,21 @@ type handler struct {
 	logger lager.Logger
 
 	scheduler       scheduler.Scheduler
+	resourceTypes   config.ResourceTypes
 	turbineEndpoint string
 }
 
 func NewHandler(
 	logger lager.Logger,
 	scheduler scheduler.Scheduler,
+	resourceTypes config.ResourceTypes,
 	turbineEndpoint string,
 ) http.Handler {
 	return &handler{
 		logger: logger,
 
 		scheduler:       scheduler,
+		resourceTypes:   resourceTypes,
 		turbineEndpoint: turbineEndpoint,
 	}
 }
@@ -41,6 +45,18 @@ func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	problems := handler.validate(build)
+	if len(problems) > 0 {
+		handler.logger.Info("invalid-build", lager.Data{
+			"problems": problems,
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(turbine.ValidationError{Problems: problems})
+		return
+	}
+
 	guid, err := uuid.NewV4()
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -55,7 +71,7 @@ func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 
 	log.Info("scheduling", lager.Data{
-		"build": build,
+		"build": build.Redacted(),
 	})
 
 	handler.scheduler.Start(build)
@@ -64,3 +80,13 @@ func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(build)
 }
+
+func (handler *handler) validate(build turbine.Build) []string {
+	problems := []string{}
+
+	if err, ok := build.Validate().(turbine.ValidationError); ok {
+		problems = append(problems, err.Problems...)
+	}
+
+	return append(problems, handler.resourceTypes.Problems(build)...)
+}
@@ -0,0 +1,67 @@
+package exportevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/scheduler"
+	"github.com/pivotal-golang/lager"
+)
+
+type handler struct {
+	logger    lager.Logger
+	scheduler scheduler.Scheduler
+}
+
+func NewHandler(logger lager.Logger, scheduler scheduler.Scheduler) http.Handler {
+	return &handler{
+		logger:    logger,
+		scheduler: scheduler,
+	}
+}
+
+// ServeHTTP writes the build's events so far as a JSON array of
+// event.Messages, with the same ids as when streamed.
+func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	guid := r.FormValue(":guid")
+
+	scheduled, found := handler.scheduler.Lookup(guid)
+	if !found {
+		handler.logger.Info("unknown-build", lager.Data{
+			"guid": guid,
+		})
+
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	messages := []event.Message{}
+	for i, e := range scheduled.EventHub.Events() {
+		messages = append(messages, event.Message{
+			ID:    fmt.Sprintf("%d", i),
+			Event: e,
+		})
+	}
+
+	// the guid is quoted or encoded as needed to keep the header well-formed
+	disposition := mime.FormatMediaType("attachment", map[string]string{
+		"filename": guid + "-events.json",
+	})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", disposition)
+	w.WriteHeader(http.StatusOK)
+
+	err := json.NewEncoder(w).Encode(messages)
+	if err != nil {
+		handler.logger.Error("failed-to-encode-events", err, lager.Data{
+			"guid": guid,
+		})
+	}
+}
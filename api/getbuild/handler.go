@@ -0,0 +1,44 @@
+package getbuild
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/scheduler"
+	"github.com/pivotal-golang/lager"
+)
+
+type handler struct {
+	logger    lager.Logger
+	scheduler scheduler.Scheduler
+}
+
+func NewHandler(logger lager.Logger, scheduler scheduler.Scheduler) http.Handler {
+	return &handler{
+		logger:    logger,
+		scheduler: scheduler,
+	}
+}
+
+func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	guid := r.FormValue(":guid")
+
+	scheduled, found := handler.scheduler.Lookup(guid)
+	if !found {
+		handler.logger.Info("unknown-build", lager.Data{
+			"guid": guid,
+		})
+
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(turbine.BuildState{
+		Build:  scheduled.Build.Redacted(),
+		Status: scheduled.Status,
+	})
+}
@@ -0,0 +1,79 @@
+package getlog
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/scheduler"
+	"github.com/pivotal-golang/lager"
+)
+
+// terminal escape sequences, e.g. colors and cursor movement
+var ansiEscapeRegexp = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+type handler struct {
+	logger    lager.Logger
+	scheduler scheduler.Scheduler
+}
+
+func NewHandler(logger lager.Logger, scheduler scheduler.Scheduler) http.Handler {
+	return &handler{
+		logger:    logger,
+		scheduler: scheduler,
+	}
+}
+
+// ServeHTTP writes the build's log output so far as plain text. The output
+// can be limited to origins, e.g. ?origin=run or ?origin=input:repo, and
+// escape sequences can be removed with ?strip_ansi=true.
+func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	guid := r.FormValue(":guid")
+
+	hLog := handler.logger.Session("build-log", lager.Data{
+		"guid": guid,
+	})
+
+	filter := event.Filter{Types: []event.EventType{event.EventTypeLog}}
+
+	for _, o := range r.URL.Query()["origin"] {
+		origin, err := event.ParseOrigin(o)
+		if err != nil {
+			hLog.Error("bad-origin", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		filter.Origins = append(filter.Origins, origin)
+	}
+
+	stripANSI := r.URL.Query().Get("strip_ansi") == "true"
+
+	scheduled, found := handler.scheduler.Lookup(guid)
+	if !found {
+		hLog.Info("unknown-build")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range scheduled.EventHub.Events() {
+		log, ok := e.(event.Log)
+		if !ok || !filter.Matches(log) {
+			continue
+		}
+
+		payload := log.Payload
+		if stripANSI {
+			payload = ansiEscapeRegexp.ReplaceAllString(payload, "")
+		}
+
+		_, err := w.Write([]byte(payload))
+		if err != nil {
+			return
+		}
+	}
+}
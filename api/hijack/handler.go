@@ -7,8 +7,8 @@ import (
 	"io"
 	"net/http"
 
-	garden_api "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/runtime"
 	"github.com/concourse/turbine/scheduler"
 	"github.com/pivotal-golang/lager"
 )
@@ -34,7 +34,7 @@ func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"guid": guid,
 	})
 
-	var spec garden_api.ProcessSpec
+	var spec runtime.ProcessSpec
 	err := json.NewDecoder(r.Body).Decode(&spec)
 	if err != nil {
 		log.Error("malformed-request", err)
@@ -57,7 +57,7 @@ func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	inR, inW := io.Pipe()
 
-	process, err := handler.scheduler.Hijack(guid, spec, garden_api.ProcessIO{
+	process, err := handler.scheduler.Hijack(guid, spec, runtime.ProcessIO{
 		Stdin:  inR,
 		Stdout: conn,
 		Stderr: conn,
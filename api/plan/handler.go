@@ -0,0 +1,115 @@
+package plan
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/config"
+	"github.com/concourse/turbine/resource"
+)
+
+type handler struct {
+	logger lager.Logger
+
+	tracker       resource.Tracker
+	resourceTypes config.ResourceTypes
+	drain         <-chan struct{}
+}
+
+func NewHandler(
+	logger lager.Logger,
+	tracker resource.Tracker,
+	resourceTypes config.ResourceTypes,
+	drain <-chan struct{},
+) http.Handler {
+	return &handler{
+		logger: logger,
+
+		tracker:       tracker,
+		resourceTypes: resourceTypes,
+		drain:         drain,
+	}
+}
+
+func (handler *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var build turbine.Build
+	err := json.NewDecoder(r.Body).Decode(&build)
+	if err != nil {
+		handler.logger.Error("malformed-request", err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	log := handler.logger.Session("plan")
+
+	problems := handler.resourceTypes.Problems(build)
+	if err, ok := build.Validate().(turbine.ValidationError); ok {
+		problems = append(err.Problems, problems...)
+	}
+
+	if len(problems) > 0 {
+		log.Info("invalid-build", lager.Data{
+			"problems": problems,
+		})
+
+		writeProblems(w, problems)
+		return
+	}
+
+	// only the config of inputs providing one is needed; their bits are
+	// never streamed anywhere
+	for i, input := range build.Inputs {
+		if input.ConfigPath == "" {
+			continue
+		}
+
+		fetched, inputConfig, err := handler.fetchConfig(input)
+		if err != nil {
+			log.Error("failed-to-fetch-config", err, lager.Data{
+				"input": input.Name,
+			})
+
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		build.Inputs[i] = fetched
+		build.Config = inputConfig.Merge(build.Config)
+	}
+
+	if err, ok := build.ValidateMerged().(turbine.ValidationError); ok {
+		log.Info("invalid-merged-build", lager.Data{
+			"problems": err.Problems,
+		})
+
+		writeProblems(w, err.Problems)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(build.Plan(resource.ResourcesDir))
+}
+
+func (handler *handler) fetchConfig(input turbine.Input) (turbine.Input, turbine.Config, error) {
+	resource, err := handler.tracker.Init(input.Type, ioutil.Discard, nil, handler.drain)
+	if err != nil {
+		return turbine.Input{}, turbine.Config{}, err
+	}
+
+	defer handler.tracker.Release(resource)
+
+	return resource.FetchConfig(input)
+}
+
+func writeProblems(w http.ResponseWriter, problems []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(turbine.ValidationError{Problems: problems})
+}
@@ -1,5 +1,10 @@
 package turbine
 
+import (
+	"strconv"
+	"strings"
+)
+
 type Status string
 
 const (
@@ -10,6 +15,13 @@ const (
 	StatusAborted   Status = "aborted"
 )
 
+// BuildState is a build as turbine last computed it, i.e. with the versions
+// of its fetched inputs and performed outputs, along with its status.
+type BuildState struct {
+	Build  Build  `json:"build"`
+	Status Status `json:"status"`
+}
+
 type Build struct {
 	Guid string `json:"guid"`
 
@@ -26,6 +38,10 @@ type Config struct {
 	Params map[string]string `json:"params,omitempty"  yaml:"params"`
 	Run    RunConfig         `json:"run,omitempty"     yaml:"run"`
 	Inputs []InputConfig     `json:"inputs,omitempty"  yaml:"inputs"`
+
+	// like params, but never emitted in events, snapshots, or logs, and
+	// masked in build output
+	SecretParams map[string]string `json:"secret_params,omitempty" yaml:"secret_params"`
 }
 
 type RunConfig struct {
@@ -62,6 +78,17 @@ type Input struct {
 
 	// path to build configuration provided by this input
 	ConfigPath string `json:"config_path"`
+
+	// retries fetching the input if its script fails
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// limits how long each of the input's scripts may run, overriding the
+	// resource type's default
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// report the input as an output of the build if it succeeds, without
+	// performing it
+	Republish bool `json:"republish,omitempty"`
 }
 
 type Version map[string]interface{}
@@ -90,14 +117,44 @@ type Output struct {
 
 	// e.g. commit_author, commit_date, commit_sha
 	Metadata []MetadataField `json:"metadata,omitempty"`
+
+	// retries performing the output if its script fails
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// limits how long the output's script may run, overriding the resource
+	// type's default
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// names of outputs that must be performed before this one; their
+	// versions may be referred to in its params and source
+	DependsOn []string `json:"depends_on,omitempty"`
 }
 
 type OutputConditions []OutputCondition
 
-func (cs OutputConditions) SatisfiedBy(exitStatus int) bool {
-	for _, status := range cs {
-		if (status == OutputConditionSuccess && exitStatus == 0) ||
-			(status == OutputConditionFailure && exitStatus != 0) {
+// SatisfiedBy returns true if any of the conditions hold for a build that
+// ended with the given status. The exit status is only considered if the
+// build's process exited, i.e. it succeeded or failed.
+func (cs OutputConditions) SatisfiedBy(status Status, exitStatus int) bool {
+	for _, c := range cs {
+		if c.satisfiedBy(status, exitStatus) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// satisfiedByAnyFailure returns true if the conditions hold for some nonzero
+// exit status.
+func (cs OutputConditions) satisfiedByAnyFailure() bool {
+	for _, c := range cs {
+		if c == OutputConditionFailure {
+			return true
+		}
+
+		_, max, ok := c.exitRange()
+		if ok && max > 0 {
 			return true
 		}
 	}
@@ -105,13 +162,74 @@ func (cs OutputConditions) SatisfiedBy(exitStatus int) bool {
 	return false
 }
 
+// OutputCondition is one of success, failure, aborted, errored, or an exit
+// status range of the form exit:N or exit:N-M (inclusive).
 type OutputCondition string
 
 const (
 	OutputConditionSuccess OutputCondition = "success"
 	OutputConditionFailure OutputCondition = "failure"
+	OutputConditionAborted OutputCondition = "aborted"
+	OutputConditionErrored OutputCondition = "errored"
+
+	outputConditionExitPrefix = "exit:"
 )
 
+func (c OutputCondition) IsValid() bool {
+	switch c {
+	case OutputConditionSuccess, OutputConditionFailure, OutputConditionAborted, OutputConditionErrored:
+		return true
+	default:
+		_, _, ok := c.exitRange()
+		return ok
+	}
+}
+
+func (c OutputCondition) satisfiedBy(status Status, exitStatus int) bool {
+	switch c {
+	case OutputConditionSuccess:
+		return status == StatusSucceeded
+	case OutputConditionFailure:
+		return status == StatusFailed
+	case OutputConditionAborted:
+		return status == StatusAborted
+	case OutputConditionErrored:
+		return status == StatusErrored
+	}
+
+	if status != StatusSucceeded && status != StatusFailed {
+		return false
+	}
+
+	min, max, ok := c.exitRange()
+	return ok && exitStatus >= min && exitStatus <= max
+}
+
+func (c OutputCondition) exitRange() (int, int, bool) {
+	str := string(c)
+	if !strings.HasPrefix(str, outputConditionExitPrefix) {
+		return 0, 0, false
+	}
+
+	bounds := strings.SplitN(strings.TrimPrefix(str, outputConditionExitPrefix), "-", 2)
+
+	min, err := strconv.Atoi(bounds[0])
+	if err != nil || min < 0 {
+		return 0, 0, false
+	}
+
+	if len(bounds) == 1 {
+		return min, min, true
+	}
+
+	max, err := strconv.Atoi(bounds[1])
+	if err != nil || max < min {
+		return 0, 0, false
+	}
+
+	return min, max, true
+}
+
 type Source map[string]interface{}
 
 type Params map[string]interface{}
@@ -6,15 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"path"
-	"time"
-
-	gapi "github.com/cloudfoundry-incubator/garden/api"
+	"sync"
 
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/builder/inputs"
 	"github.com/concourse/turbine/builder/outputs"
 	"github.com/concourse/turbine/event"
 	"github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/runtime"
 )
 
 var ErrAborted = errors.New("build aborted")
@@ -34,55 +33,78 @@ type Builder interface {
 
 	// attach to a running build, forwarding output events
 	//
+	// if the build's process could not be waited on, the exited build is
+	// returned with the error so that its outputs may still be performed
+	//
 	// this will be called again after turbine restarts
 	Attach(RunningBuild, event.Emitter, <-chan struct{}) (ExitedBuild, error)
 
 	// execute an arbitrary process in a running container
-	Hijack(string, gapi.ProcessSpec, gapi.ProcessIO) (gapi.Process, error)
+	Hijack(string, runtime.ProcessSpec, runtime.ProcessIO) (runtime.Process, error)
 
 	// process an exited build's outputs
 	Finish(ExitedBuild, event.Emitter, <-chan struct{}) (turbine.Build, error)
+
+	// emit a running build's buffered output, e.g. before snapshotting it
+	FlushLogs(guid string)
 }
 
 type RunningBuild struct {
 	Build turbine.Build
 
-	Container gapi.Container
+	Container runtime.Container
 
 	ProcessID uint32
-	Process   gapi.Process
+	Process   runtime.Process
 }
 
 type ExitedBuild struct {
 	Build turbine.Build
 
-	Container gapi.Container
+	Container runtime.Container
+
+	// succeeded or failed if the process exited; aborted if it was stopped;
+	// errored if it could not be waited on
+	Status turbine.Status
 
 	ExitStatus int
 }
 
 type builder struct {
-	gardenClient    gapi.Client
+	runtime         runtime.Runtime
 	inputFetcher    inputs.Fetcher
 	outputPerformer outputs.Performer
+	clock           event.Clock
+
+	// output of running builds' processes, by guid, closed once they exit
+	logs  map[string][]*event.Writer
+	logsL *sync.Mutex
 }
 
 func NewBuilder(
-	gardenClient gapi.Client,
+	containerRuntime runtime.Runtime,
 	inputFetcher inputs.Fetcher,
 	outputPerformer outputs.Performer,
+	clock event.Clock,
 ) Builder {
 	return &builder{
-		gardenClient:    gardenClient,
+		runtime:         containerRuntime,
 		inputFetcher:    inputFetcher,
 		outputPerformer: outputPerformer,
+		clock:           clock,
+
+		logs:  make(map[string][]*event.Writer),
+		logsL: new(sync.Mutex),
 	}
 }
 
 func (builder *builder) Start(build turbine.Build, emitter event.Emitter, abort <-chan struct{}) (RunningBuild, error) {
-	fetchedInputs, err := builder.inputFetcher.Fetch(build.Inputs, emitter, abort)
+	// inputs may configure more secrets; mask what we know of while fetching
+	fetchEmitter := event.NewMaskingEmitter(emitter, build.Config.Secrets())
+
+	fetchedInputs, err := builder.inputFetcher.Fetch(build.Inputs, fetchEmitter, build.Config.Secrets(), abort)
 	if err != nil {
-		return RunningBuild{}, builder.emitError(emitter, "failed to fetch inputs", err)
+		return RunningBuild{}, builder.emitError(fetchEmitter, "failed to fetch inputs", err)
 	}
 
 	newInputs := make([]turbine.Input, len(fetchedInputs))
@@ -95,8 +117,24 @@ func (builder *builder) Start(build turbine.Build, emitter event.Emitter, abort
 
 	build.Inputs = newInputs
 
+	err = build.ValidateMerged()
+	if err != nil {
+		fetchEmitter.EmitEvent(event.Error{
+			Message: err.Error(),
+		})
+
+		return RunningBuild{}, err
+	}
+
+	build.Config, err = build.Config.Interpolate(build.Inputs)
+	if err != nil {
+		return RunningBuild{}, builder.emitError(fetchEmitter, "failed to interpolate config", err)
+	}
+
+	emitter = event.NewMaskingEmitter(emitter, build.Config.Secrets())
+
 	emitter.EmitEvent(event.Initialize{
-		BuildConfig: build.Config,
+		BuildConfig: build.Config.Redacted(),
 	})
 
 	container, err := builder.createBuildContainer(build.Guid, build.Config, build.Privileged)
@@ -109,17 +147,17 @@ func (builder *builder) Start(build turbine.Build, emitter event.Emitter, abort
 		return RunningBuild{}, builder.emitError(emitter, "failed to stream in resources", err)
 	}
 
-	emitter.EmitEvent(event.Start{
-		Time: time.Now().Unix(),
-	})
+	emitter.EmitEvent(event.Start{})
+
+	processIO := builder.emitterProcessIO(build.Guid, emitter, build.Config.Secrets())
 
 	process, err := builder.runBuild(
 		container,
-		emitterProcessIO(emitter),
-		build.Config,
-		build.Privileged,
+		processIO,
+		build,
 	)
 	if err != nil {
+		builder.closeLogs(build.Guid)
 		return RunningBuild{}, builder.emitError(emitter, "failed to run", err)
 	}
 
@@ -134,8 +172,10 @@ func (builder *builder) Start(build turbine.Build, emitter event.Emitter, abort
 }
 
 func (builder *builder) Attach(running RunningBuild, emitter event.Emitter, abort <-chan struct{}) (ExitedBuild, error) {
+	emitter = event.NewMaskingEmitter(emitter, running.Build.Config.Secrets())
+
 	if running.Container == nil {
-		container, err := builder.gardenClient.Lookup(running.Build.Guid)
+		container, err := builder.runtime.Lookup(running.Build.Guid)
 		if err != nil {
 			return ExitedBuild{}, builder.emitError(emitter, "failed to lookup container", err)
 		}
@@ -144,35 +184,71 @@ func (builder *builder) Attach(running RunningBuild, emitter event.Emitter, abor
 	}
 
 	if running.Process == nil {
+		processIO := builder.emitterProcessIO(running.Build.Guid, emitter, running.Build.Config.Secrets())
+
 		process, err := running.Container.Attach(
 			running.ProcessID,
-			emitterProcessIO(emitter),
+			processIO,
 		)
 		if err != nil {
+			builder.closeLogs(running.Build.Guid)
 			return ExitedBuild{}, builder.emitError(emitter, "failed to attach to process", err)
 		}
 
 		running.Process = process
 	}
 
-	status, err := builder.waitForRunToEnd(running, abort)
-	if err != nil {
-		return ExitedBuild{}, builder.emitError(emitter, "running failed", err)
-	}
-
-	return ExitedBuild{
+	exited := ExitedBuild{
 		Build:     running.Build,
 		Container: running.Container,
+	}
 
-		ExitStatus: status,
-	}, nil
+	status, err := builder.waitForRunToEnd(running, abort)
+
+	// emit the remaining output before the build's outcome
+	builder.closeLogs(running.Build.Guid)
+
+	switch err {
+	case nil:
+		exited.ExitStatus = status
+
+		if status == 0 {
+			exited.Status = turbine.StatusSucceeded
+		} else {
+			exited.Status = turbine.StatusFailed
+		}
+
+		return exited, nil
+
+	case ErrAborted:
+		// the container has stopped; outputs may still be performed
+		builder.emitError(emitter, "running failed", err)
+
+		exited.Status = turbine.StatusAborted
+		exited.ExitStatus = status
+
+		return exited, nil
+
+	default:
+		exited.Status = turbine.StatusErrored
+
+		return exited, builder.emitError(emitter, "running failed", err)
+	}
 }
 
 func (builder *builder) Finish(exited ExitedBuild, emitter event.Emitter, abort <-chan struct{}) (turbine.Build, error) {
-	emitter.EmitEvent(event.Finish{
-		Time:       time.Now().Unix(),
-		ExitStatus: exited.ExitStatus,
-	})
+	emitter = event.NewMaskingEmitter(emitter, exited.Build.Config.Secrets())
+
+	if exited.Status != turbine.StatusErrored {
+		emitter.EmitEvent(event.Finish{
+			ExitStatus: exited.ExitStatus,
+		})
+	}
+
+	if exited.Status == turbine.StatusAborted {
+		// the build has already been aborted; its outputs should still run
+		abort = nil
+	}
 
 	outputs, err := builder.performOutputs(exited.Container, exited, emitter, abort)
 	if err != nil {
@@ -184,8 +260,8 @@ func (builder *builder) Finish(exited ExitedBuild, emitter event.Emitter, abort
 	return exited.Build, nil
 }
 
-func (builder *builder) Hijack(guid string, spec gapi.ProcessSpec, io gapi.ProcessIO) (gapi.Process, error) {
-	container, err := builder.gardenClient.Lookup(guid)
+func (builder *builder) Hijack(guid string, spec runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
+	container, err := builder.runtime.Lookup(guid)
 	if err != nil {
 		return nil, err
 	}
@@ -205,12 +281,12 @@ func (builder *builder) createBuildContainer(
 	buildGuid string,
 	buildConfig turbine.Config,
 	privileged bool,
-) (gapi.Container, error) {
+) (runtime.Container, error) {
 	if buildConfig.Image == "" {
 		return nil, ErrNoImageSpecified
 	}
 
-	return builder.gardenClient.Create(gapi.ContainerSpec{
+	return builder.runtime.Create(runtime.ContainerSpec{
 		Handle:     buildGuid,
 		RootFSPath: buildConfig.Image,
 		Privileged: privileged,
@@ -218,7 +294,7 @@ func (builder *builder) createBuildContainer(
 }
 
 func (builder *builder) streamInResources(
-	container gapi.Container,
+	container runtime.Container,
 	fetchedInputs []inputs.FetchedInput,
 	configuredInputs []turbine.InputConfig,
 ) error {
@@ -259,7 +335,7 @@ func (builder *builder) streamInResources(
 	return nil
 }
 
-func (builder *builder) makeEmptySources(container gapi.Container) error {
+func (builder *builder) makeEmptySources(container runtime.Container) error {
 	emptyTar := new(bytes.Buffer)
 
 	err := tar.NewWriter(emptyTar).Close()
@@ -276,25 +352,19 @@ func (builder *builder) makeEmptySources(container gapi.Container) error {
 }
 
 func (builder *builder) runBuild(
-	container gapi.Container,
-	processIO gapi.ProcessIO,
-	buildConfig turbine.Config,
-	privileged bool,
-) (gapi.Process, error) {
-	env := []string{}
-	for n, v := range buildConfig.Params {
-		env = append(env, n+"="+v)
-	}
-
-	return container.Run(gapi.ProcessSpec{
-		Privileged: privileged,
-
-		Path: buildConfig.Run.Path,
-		Args: buildConfig.Run.Args,
-		Env:  env,
+	container runtime.Container,
+	processIO runtime.ProcessIO,
+	build turbine.Build,
+) (runtime.Process, error) {
+	return container.Run(runtime.ProcessSpec{
+		Privileged: build.Privileged,
+
+		Path: build.Config.Run.Path,
+		Args: build.Config.Run.Args,
+		Env:  buildEnv(build),
 		Dir:  resource.ResourcesDir,
 
-		TTY: &gapi.TTYSpec{},
+		TTY: &runtime.TTYSpec{},
 	}, processIO)
 }
 
@@ -336,38 +406,154 @@ func (builder *builder) waitForRunToEnd(
 }
 
 func (builder *builder) performOutputs(
-	container gapi.Container,
+	container runtime.Container,
 	build ExitedBuild,
 	emitter event.Emitter,
 	abort <-chan struct{},
 ) ([]turbine.Output, error) {
-	implicitOutputs := []turbine.Output{}
 	outputsToPerform := []turbine.Output{}
+	explicitOutputs := map[string]bool{}
 	for _, output := range build.Build.Outputs {
-		if !output.On.SatisfiedBy(build.ExitStatus) {
+		if !output.On.SatisfiedBy(build.Status, build.ExitStatus) {
 			continue
 		}
 
 		outputsToPerform = append(outputsToPerform, output)
+		explicitOutputs[output.Name] = true
 	}
 
-	performedOutputs, err := builder.outputPerformer.PerformOutputs(container, outputsToPerform, emitter, abort)
+	outputsToPerform = withoutUnperformedDependencies(outputsToPerform, emitter)
+
+	performedOutputs, err := builder.outputPerformer.PerformOutputs(container, outputsToPerform, emitter, build.Build.Config.Secrets(), abort)
 	if err != nil {
 		return nil, err
 	}
 
+	implicitOutputs := []turbine.Output{}
+
+	if build.Status == turbine.StatusSucceeded {
+		for _, input := range build.Build.Inputs {
+			if !input.Republish {
+				continue
+			}
+
+			output := implicitOutput(input)
+
+			// an explicit output of the same resource takes precedence
+			if explicitOutputs[output.Name] {
+				continue
+			}
+
+			emitter.EmitEvent(event.Output{Output: output})
+
+			implicitOutputs = append(implicitOutputs, output)
+		}
+	}
+
 	return append(implicitOutputs, performedOutputs...), nil
 }
 
-func emitterProcessIO(emitter event.Emitter) gapi.ProcessIO {
-	return gapi.ProcessIO{
-		Stdout: event.NewWriter(emitter, event.Origin{
-			Type: event.OriginTypeRun,
-			Name: "stdout",
-		}),
-		Stderr: event.NewWriter(emitter, event.Origin{
-			Type: event.OriginTypeRun,
-			Name: "stderr",
-		}),
+// withoutUnperformedDependencies skips outputs that depend on outputs which
+// don't run for the build's result, e.g. a success output depending on a
+// failure output. This is not an error, as the build did what it was told,
+// so it's only logged; dependencies that fail are reported by the performer.
+func withoutUnperformedDependencies(toPerform []turbine.Output, emitter event.Emitter) []turbine.Output {
+	for {
+		performing := map[string]bool{}
+		for _, output := range toPerform {
+			performing[output.Name] = true
+		}
+
+		remaining := []turbine.Output{}
+		for _, output := range toPerform {
+			skipped := false
+
+			for _, dependency := range output.DependsOn {
+				if !performing[dependency] {
+					emitter.EmitEvent(event.Log{
+						Payload: fmt.Sprintf(
+							"skipping output '%s': depends on output '%s', which does not run for this build's result\n",
+							output.Name,
+							dependency,
+						),
+						Origin: event.Origin{
+							Type: event.OriginTypeOutput,
+							Name: output.Name,
+						},
+					})
+
+					skipped = true
+					break
+				}
+			}
+
+			if !skipped {
+				remaining = append(remaining, output)
+			}
+		}
+
+		// skipping an output may leave its own dependents without it
+		if len(remaining) == len(toPerform) {
+			return remaining
+		}
+
+		toPerform = remaining
+	}
+}
+
+// implicitOutput reports a fetched input as an output of its resource
+func implicitOutput(input turbine.Input) turbine.Output {
+	name := input.Resource
+	if name == "" {
+		name = input.Name
+	}
+
+	return turbine.Output{
+		Name:     name,
+		Type:     input.Type,
+		Source:   input.Source,
+		Version:  input.Version,
+		Metadata: input.Metadata,
+	}
+}
+
+func (builder *builder) FlushLogs(guid string) {
+	builder.logsL.Lock()
+	logs := builder.logs[guid]
+	builder.logsL.Unlock()
+
+	for _, log := range logs {
+		log.Flush()
+	}
+}
+
+// emitterProcessIO returns process io that emits the build's output, tracked
+// until closeLogs is called
+func (builder *builder) emitterProcessIO(guid string, emitter event.Emitter, secrets []string) runtime.ProcessIO {
+	stdout := event.NewWriter(emitter, event.Origin{
+		Type: event.OriginTypeRun,
+		Name: "stdout",
+	}, builder.clock, secrets)
+
+	stderr := event.NewWriter(emitter, event.Origin{
+		Type: event.OriginTypeRun,
+		Name: "stderr",
+	}, builder.clock, secrets)
+
+	builder.logsL.Lock()
+	builder.logs[guid] = []*event.Writer{stdout, stderr}
+	builder.logsL.Unlock()
+
+	return runtime.ProcessIO{Stdout: stdout, Stderr: stderr}
+}
+
+func (builder *builder) closeLogs(guid string) {
+	builder.logsL.Lock()
+	logs := builder.logs[guid]
+	delete(builder.logs, guid)
+	builder.logsL.Unlock()
+
+	for _, log := range logs {
+		log.Close()
 	}
 }
@@ -24,6 +24,9 @@ import (
 	efakes "github.com/concourse/turbine/event/fakes"
 	"github.com/concourse/turbine/event/testlog"
 	"github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/runtime"
+	rtfakes "github.com/concourse/turbine/runtime/fakes"
+	gardenruntime "github.com/concourse/turbine/runtime/garden"
 )
 
 var _ = Describe("Builder", func() {
@@ -51,7 +54,14 @@ var _ = Describe("Builder", func() {
 		inputFetcher = new(ifakes.FakeFetcher)
 		outputPerformer = new(ofakes.FakePerformer)
 
-		builder = NewBuilder(gardenClient, inputFetcher, outputPerformer)
+		// flush output as soon as it's written
+		flushNow := make(chan time.Time)
+		close(flushNow)
+
+		clock := new(efakes.FakeClock)
+		clock.AfterReturns(flushNow)
+
+		builder = NewBuilder(gardenruntime.New(gardenClient), inputFetcher, outputPerformer, clock)
 
 		build = turbine.Build{
 			Guid: "some-build-guid",
@@ -146,7 +156,7 @@ var _ = Describe("Builder", func() {
 					},
 				}
 
-				inputFetcher.FetchStub = func(fetchInputs []turbine.Input, fetchEmitter event.Emitter, fetchAbort <-chan struct{}) ([]inputs.FetchedInput, error) {
+				inputFetcher.FetchStub = func(fetchInputs []turbine.Input, fetchEmitter event.Emitter, fetchSecrets []string, fetchAbort <-chan struct{}) ([]inputs.FetchedInput, error) {
 					Ω(fetchInputs).Should(Equal(build.Inputs))
 					Ω(fetchEmitter).Should(Equal(emitter))
 
@@ -206,7 +216,15 @@ var _ = Describe("Builder", func() {
 				Ω(handle).Should(Equal("some-build-guid"))
 				Ω(spec.Path).Should(Equal("./bin/test"))
 				Ω(spec.Args).Should(Equal([]string{"arg1", "arg2"}))
-				Ω(spec.Env).Should(ConsistOf("FOO=bar", "BAZ=buzz"))
+				Ω(spec.Env).Should(ConsistOf(
+					"FOO=bar",
+					"BAZ=buzz",
+					"BUILD_GUID=some-build-guid",
+					`BUILD_INPUT_FIRST_RESOURCE_VERSION={"version":"1"}`,
+					"BUILD_INPUT_FIRST_RESOURCE_VERSION_VERSION=1",
+					`BUILD_INPUT_SECOND_RESOURCE_VERSION={"version":"2"}`,
+					"BUILD_INPUT_SECOND_RESOURCE_VERSION_VERSION=2",
+				))
 				Ω(spec.Dir).Should(Equal("/tmp/build/src"))
 				Ω(spec.TTY).Should(Equal(&garden.TTYSpec{}))
 				Ω(spec.Privileged).Should(BeFalse())
@@ -235,7 +253,8 @@ var _ = Describe("Builder", func() {
 				for _, ev := range events.Sent() {
 					switch startEvent := ev.(type) {
 					case event.Start:
-						Ω(startEvent.Time).Should(BeNumerically("~", time.Now().Unix()))
+						// left for the hub to timestamp
+						Ω(startEvent.Time).Should(BeZero())
 					}
 				}
 			})
@@ -245,16 +264,22 @@ var _ = Describe("Builder", func() {
 					build.Config.Image = ""
 				})
 
-				It("returns an error", func() {
-					Ω(startErr).Should(Equal(ErrNoImageSpecified))
+				It("returns a validation error", func() {
+					Ω(startErr).Should(Equal(turbine.ValidationError{
+						Problems: []string{"no image specified"},
+					}))
 				})
 
 				It("errors", func() {
 					Eventually(events.Sent).Should(ContainElement(event.Error{
-						Message: "failed to create container: no image specified",
+						Message: "invalid build: no image specified",
 					}))
 				})
 
+				It("does not create a container", func() {
+					Ω(gardenClient.Connection.CreateCallCount()).Should(BeZero())
+				})
+
 				Context("but an input configured an image", func() {
 					BeforeEach(func() {
 						fetchedInputs[0].Config = turbine.Config{
@@ -338,8 +363,10 @@ var _ = Describe("Builder", func() {
 							}
 						})
 
-						It("returns an error", func() {
-							Ω(startErr).Should(Equal(UnsatisfiedInputError{"some-bogus-input"}))
+						It("returns a validation error", func() {
+							Ω(startErr).Should(Equal(turbine.ValidationError{
+								Problems: []string{"config refers to unknown input 'some-bogus-input'"},
+							}))
 						})
 					})
 				})
@@ -354,7 +381,7 @@ var _ = Describe("Builder", func() {
 				It("aborts fetching", func() {
 					Ω(startErr).Should(Equal(resource.ErrAborted))
 
-					_, _, fetchAbort := inputFetcher.FetchArgsForCall(0)
+					_, _, _, fetchAbort := inputFetcher.FetchArgsForCall(0)
 
 					Ω(fetchAbort).ShouldNot(BeClosed())
 
@@ -417,7 +444,7 @@ var _ = Describe("Builder", func() {
 				})
 
 				It("emits a build log event", func() {
-					Eventually(events.Sent).Should(ContainElement(event.Log{
+					Eventually(events.Logs).Should(ContainElement(event.Log{
 						Payload: "some stdout data",
 						Origin: event.Origin{
 							Type: event.OriginTypeRun,
@@ -425,7 +452,7 @@ var _ = Describe("Builder", func() {
 						},
 					}))
 
-					Eventually(events.Sent).Should(ContainElement(event.Log{
+					Eventually(events.Logs).Should(ContainElement(event.Log{
 						Payload: "some stderr data",
 						Origin: event.Origin{
 							Type: event.OriginTypeRun,
@@ -435,6 +462,183 @@ var _ = Describe("Builder", func() {
 				})
 			})
 
+			Context("when the merged config has many problems", func() {
+				BeforeEach(func() {
+					build.Config.Image = ""
+					build.Config.Run.Path = ""
+					build.Config.Inputs = []turbine.InputConfig{
+						{Name: "some-bogus-input"},
+					}
+				})
+
+				It("emits all of them in a single error event", func() {
+					Eventually(events.Sent).Should(ContainElement(event.Error{
+						Message: "invalid build: no image specified; no run path specified; config refers to unknown input 'some-bogus-input'",
+					}))
+				})
+
+				It("returns them as a validation error", func() {
+					Ω(startErr).Should(Equal(turbine.ValidationError{
+						Problems: []string{
+							"no image specified",
+							"no run path specified",
+							"config refers to unknown input 'some-bogus-input'",
+						},
+					}))
+				})
+
+				It("releases each resource", func() {
+					Ω(firstReleased).Should(BeClosed())
+					Ω(secondReleased).Should(BeClosed())
+				})
+			})
+
+			Context("when the config references input versions and metadata", func() {
+				BeforeEach(func() {
+					build.Config.Params["VERSION"] = "v((inputs.first-resource.version.version))"
+					build.Config.Run.Args = []string{"--key", "((inputs.second-resource.metadata.key))"}
+				})
+
+				It("interpolates them from the fetched inputs", func() {
+					_, spec, _ := gardenClient.Connection.RunArgsForCall(0)
+					Ω(spec.Args).Should(Equal([]string{"--key", "meta-2"}))
+					Ω(spec.Env).Should(ContainElement("VERSION=v1"))
+				})
+
+				It("emits the interpolated config", func() {
+					Eventually(events.Sent).Should(ContainElement(event.Initialize{
+						BuildConfig: turbine.Config{
+							Image: "some-rootfs",
+
+							Params: map[string]string{
+								"FOO":     "bar",
+								"BAZ":     "buzz",
+								"VERSION": "v1",
+							},
+
+							Run: turbine.RunConfig{
+								Path: "./bin/test",
+								Args: []string{"--key", "meta-2"},
+							},
+						},
+					}))
+				})
+
+				Context("when a reference cannot be resolved", func() {
+					BeforeEach(func() {
+						build.Config.Params["BOGUS"] = "((inputs.bogus-resource.version.version))"
+					})
+
+					It("returns an error", func() {
+						Ω(startErr).Should(Equal(turbine.UnresolvedReferenceError{
+							Reference: "inputs.bogus-resource.version.version",
+							Reason:    "unknown input",
+						}))
+					})
+
+					It("emits an error event", func() {
+						Eventually(events.Sent).Should(ContainElement(event.Error{
+							Message: "failed to interpolate config: cannot resolve ((inputs.bogus-resource.version.version)): unknown input",
+						}))
+					})
+
+					It("does not create a container", func() {
+						Ω(gardenClient.Connection.CreateCallCount()).Should(BeZero())
+					})
+				})
+			})
+
+			Context("when the build has secret params", func() {
+				BeforeEach(func() {
+					build.Config.SecretParams = map[string]string{
+						"TOKEN": "s3cr3t",
+						"FOO":   "secret-foo",
+					}
+
+					inputFetcher.FetchStub = func(fetchInputs []turbine.Input, fetchEmitter event.Emitter, fetchSecrets []string, fetchAbort <-chan struct{}) ([]inputs.FetchedInput, error) {
+						fetchEmitter.EmitEvent(event.Log{Payload: "fetching with s3cr3t"})
+						return fetchedInputs, nil
+					}
+
+					gardenClient.Connection.RunStub = func(handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+						go func() {
+							defer GinkgoRecover()
+
+							_, err := io.Stdout.Write([]byte("token is s3cr3t"))
+							Ω(err).ShouldNot(HaveOccurred())
+						}()
+
+						return new(gfakes.FakeProcess), nil
+					}
+				})
+
+				It("runs the build with them in its environment, overriding params", func() {
+					_, spec, _ := gardenClient.Connection.RunArgsForCall(0)
+					Ω(spec.Env).Should(ContainElement("FOO=secret-foo"))
+					Ω(spec.Env).Should(ContainElement("BAZ=buzz"))
+					Ω(spec.Env).Should(ContainElement("TOKEN=s3cr3t"))
+					Ω(spec.Env).ShouldNot(ContainElement("FOO=bar"))
+				})
+
+				It("omits them from the initialize event", func() {
+					Eventually(events.Sent).Should(ContainElement(event.Initialize{
+						BuildConfig: turbine.Config{
+							Image: "some-rootfs",
+
+							Params: map[string]string{
+								"FOO": "bar",
+								"BAZ": "buzz",
+							},
+
+							Run: turbine.RunConfig{
+								Path: "./bin/test",
+								Args: []string{"arg1", "arg2"},
+							},
+						},
+					}))
+				})
+
+				It("gives them to the fetcher, so that its logs don't split them", func() {
+					_, _, secrets, _ := inputFetcher.FetchArgsForCall(0)
+					Ω(secrets).Should(Equal([]string{"secret-foo", "s3cr3t"}))
+				})
+
+				It("masks them while fetching inputs", func() {
+					Eventually(events.Logs).Should(ContainElement(event.Log{
+						Payload: "fetching with ((redacted))",
+					}))
+				})
+
+				It("masks them in build logs", func() {
+					Eventually(events.Logs).Should(ContainElement(event.Log{
+						Payload: "token is ((redacted))",
+						Origin: event.Origin{
+							Type: event.OriginTypeRun,
+							Name: "stdout",
+						},
+					}))
+				})
+
+				Context("and an input configures more", func() {
+					BeforeEach(func() {
+						fetchedInputs[0].Config = turbine.Config{
+							SecretParams: map[string]string{
+								"TOKEN":       "overridden",
+								"CONFIG_ONLY": "config-secret",
+							},
+						}
+					})
+
+					It("merges the original secret params over them", func() {
+						Ω(started.Build.Config.SecretParams).Should(Equal(map[string]string{
+							"TOKEN":       "s3cr3t",
+							"FOO":         "secret-foo",
+							"CONFIG_ONLY": "config-secret",
+						}))
+					})
+				})
+			})
+
 			Context("when creating the container fails", func() {
 				disaster := errors.New("oh no!")
 
@@ -535,10 +739,10 @@ var _ = Describe("Builder", func() {
 		})
 
 		BeforeEach(func() {
-			container, err := gardenClient.Create(garden.ContainerSpec{})
+			container, err := gardenruntime.New(gardenClient).Create(runtime.ContainerSpec{})
 			Ω(err).ShouldNot(HaveOccurred())
 
-			runningProcess := new(gfakes.FakeProcess)
+			runningProcess := new(rtfakes.FakeProcess)
 
 			runningBuild = RunningBuild{
 				Build: build,
@@ -625,7 +829,7 @@ var _ = Describe("Builder", func() {
 					})
 
 					It("emits log events for stdout/stderr", func() {
-						Eventually(events.Sent).Should(ContainElement(event.Log{
+						Eventually(events.Logs).Should(ContainElement(event.Log{
 							Payload: "stdout\n",
 							Origin: event.Origin{
 								Type: event.OriginTypeRun,
@@ -633,7 +837,7 @@ var _ = Describe("Builder", func() {
 							},
 						}))
 
-						Eventually(events.Sent).Should(ContainElement(event.Log{
+						Eventually(events.Logs).Should(ContainElement(event.Log{
 							Payload: "stderr\n",
 							Origin: event.Origin{
 								Type: event.OriginTypeRun,
@@ -642,6 +846,31 @@ var _ = Describe("Builder", func() {
 						}))
 					})
 				})
+
+				Context("and the build has secret params", func() {
+					BeforeEach(func() {
+						runningBuild.Build.Config.SecretParams = map[string]string{
+							"TOKEN": "s3cr3t",
+						}
+
+						gardenClient.Connection.AttachStub = func(handle string, pid uint32, io garden.ProcessIO) (garden.Process, error) {
+							_, err := fmt.Fprintf(io.Stdout, "token is s3cr3t\n")
+							Ω(err).ShouldNot(HaveOccurred())
+
+							return new(gfakes.FakeProcess), nil
+						}
+					})
+
+					It("masks them in the logs", func() {
+						Eventually(events.Logs).Should(ContainElement(event.Log{
+							Payload: "token is ((redacted))\n",
+							Origin: event.Origin{
+								Type: event.OriginTypeRun,
+								Name: "stdout",
+							},
+						}))
+					})
+				})
 			})
 
 			Context("and attaching fails", func() {
@@ -673,7 +902,7 @@ var _ = Describe("Builder", func() {
 					close(abort)
 				}()
 
-				process := new(gfakes.FakeProcess)
+				process := new(rtfakes.FakeProcess)
 				process.WaitStub = func() (int, error) {
 					close(waiting)
 					<-stopping
@@ -696,8 +925,10 @@ var _ = Describe("Builder", func() {
 				Ω(kill).Should(BeFalse())
 			})
 
-			It("returns an error", func() {
-				Ω(attachErr).Should(HaveOccurred())
+			It("returns the build as aborted once it has stopped", func() {
+				Ω(attachErr).ShouldNot(HaveOccurred())
+				Ω(exitedBuild.Status).Should(Equal(turbine.StatusAborted))
+				Ω(exitedBuild.Container).Should(Equal(runningBuild.Container))
 			})
 
 			It("emits an error event", func() {
@@ -707,25 +938,56 @@ var _ = Describe("Builder", func() {
 			})
 		})
 
+		Context("when waiting on the build's script fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				process := new(rtfakes.FakeProcess)
+				process.WaitReturns(0, disaster)
+
+				runningBuild.Process = process
+			})
+
+			It("returns the error with the build as errored", func() {
+				Ω(attachErr).Should(Equal(disaster))
+				Ω(exitedBuild.Status).Should(Equal(turbine.StatusErrored))
+				Ω(exitedBuild.Container).Should(Equal(runningBuild.Container))
+			})
+		})
+
 		Context("when the build's script exits", func() {
 			BeforeEach(func() {
-				process := new(gfakes.FakeProcess)
+				process := new(rtfakes.FakeProcess)
 				process.WaitReturns(2, nil)
 
 				runningBuild.Process = process
 			})
 
 			It("returns the exited build with the status present", func() {
+				Ω(exitedBuild.Status).Should(Equal(turbine.StatusFailed))
 				Ω(exitedBuild.ExitStatus).Should(Equal(2))
 			})
 		})
+
+		Context("when the build's script exits successfully", func() {
+			BeforeEach(func() {
+				process := new(rtfakes.FakeProcess)
+				process.WaitReturns(0, nil)
+
+				runningBuild.Process = process
+			})
+
+			It("returns the exited build as succeeded", func() {
+				Ω(exitedBuild.Status).Should(Equal(turbine.StatusSucceeded))
+			})
+		})
 	})
 
 	Describe("Hijack", func() {
-		var spec garden.ProcessSpec
-		var io garden.ProcessIO
+		var spec runtime.ProcessSpec
+		var io runtime.ProcessIO
 
-		var process garden.Process
+		var process runtime.Process
 		var hijackErr error
 
 		JustBeforeEach(func() {
@@ -733,12 +995,12 @@ var _ = Describe("Builder", func() {
 		})
 
 		BeforeEach(func() {
-			spec = garden.ProcessSpec{
+			spec = runtime.ProcessSpec{
 				Path: "some-path",
 				Args: []string{"some", "args"},
 			}
 
-			io = garden.ProcessIO{
+			io = runtime.ProcessIO{
 				Stdin:  new(bytes.Buffer),
 				Stdout: new(bytes.Buffer),
 			}
@@ -766,8 +1028,14 @@ var _ = Describe("Builder", func() {
 
 					ranHandle, ranSpec, ranIO := gardenClient.Connection.RunArgsForCall(0)
 					Ω(ranHandle).Should(Equal("some-build-guid"))
-					Ω(ranSpec).Should(Equal(spec))
-					Ω(ranIO).Should(Equal(io))
+					Ω(ranSpec).Should(Equal(garden.ProcessSpec{
+						Path: "some-path",
+						Args: []string{"some", "args"},
+					}))
+					Ω(ranIO).Should(Equal(garden.ProcessIO{
+						Stdin:  io.Stdin,
+						Stdout: io.Stdout,
+					}))
 				})
 
 				It("returns the process", func() {
@@ -872,7 +1140,7 @@ var _ = Describe("Builder", func() {
 				onFailureOutput,
 			}
 
-			container, err := gardenClient.Create(garden.ContainerSpec{})
+			container, err := gardenruntime.New(gardenClient).Create(runtime.ContainerSpec{})
 			Ω(err).ShouldNot(HaveOccurred())
 
 			exitedBuild = ExitedBuild{
@@ -884,6 +1152,7 @@ var _ = Describe("Builder", func() {
 
 		Context("when the build exited with success", func() {
 			BeforeEach(func() {
+				exitedBuild.Status = turbine.StatusSucceeded
 				exitedBuild.ExitStatus = 0
 			})
 
@@ -895,7 +1164,7 @@ var _ = Describe("Builder", func() {
 					switch finishEvent := ev.(type) {
 					case event.Finish:
 						Ω(finishEvent.ExitStatus).Should(Equal(0))
-						Ω(finishEvent.Time).Should(BeNumerically("~", time.Now().Unix()))
+						Ω(finishEvent.Time).Should(BeZero())
 					}
 				}
 			})
@@ -903,7 +1172,7 @@ var _ = Describe("Builder", func() {
 			It("performs the set of 'on success' outputs", func() {
 				Ω(outputPerformer.PerformOutputsCallCount()).Should(Equal(1))
 
-				container, outputs, performingEmitter, _ := outputPerformer.PerformOutputsArgsForCall(0)
+				container, outputs, performingEmitter, _, _ := outputPerformer.PerformOutputsArgsForCall(0)
 				Ω(container).Should(Equal(exitedBuild.Container))
 				Ω(outputs).Should(Equal([]turbine.Output{
 					onSuccessOutput,
@@ -914,7 +1183,7 @@ var _ = Describe("Builder", func() {
 
 			Context("when the build is aborted", func() {
 				It("aborts performing outputs", func() {
-					_, _, _, performingAbort := outputPerformer.PerformOutputsArgsForCall(0)
+					_, _, _, _, performingAbort := outputPerformer.PerformOutputsArgsForCall(0)
 
 					Ω(performingAbort).ShouldNot(BeClosed())
 
@@ -976,10 +1245,69 @@ var _ = Describe("Builder", func() {
 					Ω(finishErr).Should(Equal(disaster))
 				})
 			})
+
+			Context("when an output depends on one that does not run on success", func() {
+				var dependentOutput turbine.Output
+				var transitiveOutput turbine.Output
+
+				BeforeEach(func() {
+					dependentOutput = turbine.Output{
+						Name:      "dependent",
+						Type:      "some-type",
+						On:        []turbine.OutputCondition{turbine.OutputConditionSuccess},
+						DependsOn: []string{"on-failure"},
+					}
+
+					transitiveOutput = turbine.Output{
+						Name:      "transitive",
+						Type:      "some-type",
+						On:        []turbine.OutputCondition{turbine.OutputConditionSuccess},
+						DependsOn: []string{"dependent"},
+					}
+
+					exitedBuild.Build.Outputs = append(exitedBuild.Build.Outputs, dependentOutput, transitiveOutput)
+				})
+
+				It("does not perform it, or outputs depending on it", func() {
+					_, outputs, _, _, _ := outputPerformer.PerformOutputsArgsForCall(0)
+					Ω(outputs).Should(Equal([]turbine.Output{
+						onSuccessOutput,
+						onSuccessOrFailureOutput,
+					}))
+				})
+
+				It("logs each skipped output", func() {
+					Ω(events.Sent()).Should(ContainElement(event.Log{
+						Payload: "skipping output 'dependent': depends on output 'on-failure', which does not run for this build's result\n",
+						Origin: event.Origin{
+							Type: event.OriginTypeOutput,
+							Name: "dependent",
+						},
+					}))
+
+					Ω(events.Sent()).Should(ContainElement(event.Log{
+						Payload: "skipping output 'transitive': depends on output 'dependent', which does not run for this build's result\n",
+						Origin: event.Origin{
+							Type: event.OriginTypeOutput,
+							Name: "transitive",
+						},
+					}))
+				})
+
+				It("does not emit an error for them", func() {
+					var errorEvent event.Error
+					Ω(events.Sent()).ShouldNot(ContainElement(BeAssignableToTypeOf(errorEvent)))
+				})
+
+				It("does not fail", func() {
+					Ω(finishErr).ShouldNot(HaveOccurred())
+				})
+			})
 		})
 
 		Context("when the build exited with failure", func() {
 			BeforeEach(func() {
+				exitedBuild.Status = turbine.StatusFailed
 				exitedBuild.ExitStatus = 2
 			})
 
@@ -991,7 +1319,7 @@ var _ = Describe("Builder", func() {
 					switch finishEvent := ev.(type) {
 					case event.Finish:
 						Ω(finishEvent.ExitStatus).Should(Equal(2))
-						Ω(finishEvent.Time).Should(BeNumerically("~", time.Now().Unix()))
+						Ω(finishEvent.Time).Should(BeZero())
 					}
 				}
 			})
@@ -999,7 +1327,7 @@ var _ = Describe("Builder", func() {
 			It("performs the set of 'on failure' outputs", func() {
 				Ω(outputPerformer.PerformOutputsCallCount()).Should(Equal(1))
 
-				container, outputs, performingEmitter, _ := outputPerformer.PerformOutputsArgsForCall(0)
+				container, outputs, performingEmitter, _, _ := outputPerformer.PerformOutputsArgsForCall(0)
 				Ω(container).Should(Equal(exitedBuild.Container))
 				Ω(outputs).Should(Equal([]turbine.Output{
 					onSuccessOrFailureOutput,
@@ -1010,7 +1338,7 @@ var _ = Describe("Builder", func() {
 
 			Context("when the build is aborted", func() {
 				It("aborts performing outputs", func() {
-					_, _, _, performingAbort := outputPerformer.PerformOutputsArgsForCall(0)
+					_, _, _, _, performingAbort := outputPerformer.PerformOutputsArgsForCall(0)
 
 					Ω(performingAbort).ShouldNot(BeClosed())
 
@@ -1073,5 +1401,116 @@ var _ = Describe("Builder", func() {
 				})
 			})
 		})
+
+		Context("when inputs are to be republished", func() {
+			BeforeEach(func() {
+				exitedBuild.Build.Inputs[0].Resource = "first-resource"
+				exitedBuild.Build.Inputs[0].Republish = true
+
+				exitedBuild.Build.Inputs[1].Resource = "on-success"
+				exitedBuild.Build.Inputs[1].Republish = true
+
+				outputPerformer.PerformOutputsStub = func(container runtime.Container, outputs []turbine.Output, emitter event.Emitter, secrets []string, abort <-chan struct{}) ([]turbine.Output, error) {
+					return outputs, nil
+				}
+			})
+
+			Context("and the build succeeded", func() {
+				BeforeEach(func() {
+					exitedBuild.Status = turbine.StatusSucceeded
+				})
+
+				implicitOutput := turbine.Output{
+					Name:    "first-resource",
+					Type:    "some-type",
+					Source:  turbine.Source{"uri": "in-source-1"},
+					Version: turbine.Version{"key": "in-version-1"},
+					Metadata: []turbine.MetadataField{
+						{Name: "first-meta-name", Value: "first-meta-value"},
+					},
+				}
+
+				It("reports them as outputs without performing them, unless explicitly performed", func() {
+					Ω(finished.Outputs).Should(Equal([]turbine.Output{
+						implicitOutput,
+						onSuccessOutput,
+						onSuccessOrFailureOutput,
+					}))
+
+					_, performing, _, _, _ := outputPerformer.PerformOutputsArgsForCall(0)
+					Ω(performing).ShouldNot(ContainElement(implicitOutput))
+				})
+
+				It("emits output events for them", func() {
+					Ω(events.Sent()).Should(ContainElement(event.Output{Output: implicitOutput}))
+				})
+			})
+
+			Context("and the build failed", func() {
+				BeforeEach(func() {
+					exitedBuild.Status = turbine.StatusFailed
+					exitedBuild.ExitStatus = 1
+				})
+
+				It("does not report them", func() {
+					Ω(finished.Outputs).Should(Equal([]turbine.Output{
+						onSuccessOrFailureOutput,
+						onFailureOutput,
+					}))
+				})
+			})
+		})
+
+		Context("when the build was aborted", func() {
+			var onAbortedOutput turbine.Output
+
+			BeforeEach(func() {
+				onAbortedOutput = turbine.Output{
+					Name: "on-aborted",
+					Type: "some-type",
+					On:   []turbine.OutputCondition{turbine.OutputConditionAborted},
+				}
+
+				exitedBuild.Build.Outputs = append(exitedBuild.Build.Outputs, onAbortedOutput)
+				exitedBuild.Status = turbine.StatusAborted
+				exitedBuild.ExitStatus = 143
+			})
+
+			It("performs the set of 'on aborted' outputs", func() {
+				Ω(outputPerformer.PerformOutputsCallCount()).Should(Equal(1))
+
+				_, outputs, _, _, _ := outputPerformer.PerformOutputsArgsForCall(0)
+				Ω(outputs).Should(Equal([]turbine.Output{onAbortedOutput}))
+			})
+
+			It("does not abort performing them", func() {
+				_, _, _, _, performingAbort := outputPerformer.PerformOutputsArgsForCall(0)
+				Ω(performingAbort).Should(BeNil())
+			})
+		})
+
+		Context("when the build errored", func() {
+			var onErroredOutput turbine.Output
+
+			BeforeEach(func() {
+				onErroredOutput = turbine.Output{
+					Name: "on-errored",
+					Type: "some-type",
+					On:   []turbine.OutputCondition{turbine.OutputConditionErrored},
+				}
+
+				exitedBuild.Build.Outputs = append(exitedBuild.Build.Outputs, onErroredOutput)
+				exitedBuild.Status = turbine.StatusErrored
+			})
+
+			It("performs the set of 'on errored' outputs", func() {
+				_, outputs, _, _, _ := outputPerformer.PerformOutputsArgsForCall(0)
+				Ω(outputs).Should(Equal([]turbine.Output{onErroredOutput}))
+			})
+
+			It("does not emit a Finish event", func() {
+				Ω(events.Sent()).ShouldNot(ContainElement(BeAssignableToTypeOf(event.Finish{})))
+			})
+		})
 	})
 })
@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/concourse/turbine"
+)
+
+// buildEnv computes the environment for the build's process: standard
+// BUILD_* variables describing the build, overridden by its params and then
+// its secret params.
+func buildEnv(build turbine.Build) []string {
+	vars := map[string]string{
+		"BUILD_GUID": build.Guid,
+	}
+
+	for _, input := range build.Inputs {
+		prefix := "BUILD_INPUT_" + envName(input.Name) + "_VERSION"
+
+		version, err := json.Marshal(input.Version)
+		if err == nil {
+			vars[prefix] = string(version)
+		}
+
+		for key, value := range input.Version {
+			str, err := turbine.VersionValueString(value)
+			if err == nil {
+				vars[prefix+"_"+envName(key)] = str
+			}
+		}
+	}
+
+	for name, value := range build.Config.Params {
+		vars[name] = value
+	}
+
+	for name, value := range build.Config.SecretParams {
+		vars[name] = value
+	}
+
+	env := make([]string, 0, len(vars))
+	for name, value := range vars {
+		env = append(env, name+"="+value)
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// e.g. "some-input" => "SOME_INPUT"
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
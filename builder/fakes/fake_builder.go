@@ -4,10 +4,10 @@ package fakes
 import (
 	"sync"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/builder"
 	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/runtime"
 )
 
 type FakeBuilder struct {
@@ -33,15 +33,15 @@ type FakeBuilder struct {
 		result1 builder.ExitedBuild
 		result2 error
 	}
-	HijackStub        func(string, garden.ProcessSpec, garden.ProcessIO) (garden.Process, error)
+	HijackStub        func(string, runtime.ProcessSpec, runtime.ProcessIO) (runtime.Process, error)
 	hijackMutex       sync.RWMutex
 	hijackArgsForCall []struct {
 		arg1 string
-		arg2 garden.ProcessSpec
-		arg3 garden.ProcessIO
+		arg2 runtime.ProcessSpec
+		arg3 runtime.ProcessIO
 	}
 	hijackReturns struct {
-		result1 garden.Process
+		result1 runtime.Process
 		result2 error
 	}
 	FinishStub        func(builder.ExitedBuild, event.Emitter, <-chan struct{}) (turbine.Build, error)
@@ -55,6 +55,11 @@ type FakeBuilder struct {
 		result1 turbine.Build
 		result2 error
 	}
+	FlushLogsStub        func(guid string)
+	flushLogsMutex       sync.RWMutex
+	flushLogsArgsForCall []struct {
+		guid string
+	}
 }
 
 func (fake *FakeBuilder) Start(arg1 turbine.Build, arg2 event.Emitter, arg3 <-chan struct{}) (builder.RunningBuild, error) {
@@ -127,12 +132,12 @@ func (fake *FakeBuilder) AttachReturns(result1 builder.ExitedBuild, result2 erro
 	}{result1, result2}
 }
 
-func (fake *FakeBuilder) Hijack(arg1 string, arg2 garden.ProcessSpec, arg3 garden.ProcessIO) (garden.Process, error) {
+func (fake *FakeBuilder) Hijack(arg1 string, arg2 runtime.ProcessSpec, arg3 runtime.ProcessIO) (runtime.Process, error) {
 	fake.hijackMutex.Lock()
 	fake.hijackArgsForCall = append(fake.hijackArgsForCall, struct {
 		arg1 string
-		arg2 garden.ProcessSpec
-		arg3 garden.ProcessIO
+		arg2 runtime.ProcessSpec
+		arg3 runtime.ProcessIO
 	}{arg1, arg2, arg3})
 	fake.hijackMutex.Unlock()
 	if fake.HijackStub != nil {
@@ -148,16 +153,16 @@ func (fake *FakeBuilder) HijackCallCount() int {
 	return len(fake.hijackArgsForCall)
 }
 
-func (fake *FakeBuilder) HijackArgsForCall(i int) (string, garden.ProcessSpec, garden.ProcessIO) {
+func (fake *FakeBuilder) HijackArgsForCall(i int) (string, runtime.ProcessSpec, runtime.ProcessIO) {
 	fake.hijackMutex.RLock()
 	defer fake.hijackMutex.RUnlock()
 	return fake.hijackArgsForCall[i].arg1, fake.hijackArgsForCall[i].arg2, fake.hijackArgsForCall[i].arg3
 }
 
-func (fake *FakeBuilder) HijackReturns(result1 garden.Process, result2 error) {
+func (fake *FakeBuilder) HijackReturns(result1 runtime.Process, result2 error) {
 	fake.HijackStub = nil
 	fake.hijackReturns = struct {
-		result1 garden.Process
+		result1 runtime.Process
 		result2 error
 	}{result1, result2}
 }
@@ -197,4 +202,27 @@ func (fake *FakeBuilder) FinishReturns(result1 turbine.Build, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *FakeBuilder) FlushLogs(guid string) {
+	fake.flushLogsMutex.Lock()
+	fake.flushLogsArgsForCall = append(fake.flushLogsArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.flushLogsMutex.Unlock()
+	if fake.FlushLogsStub != nil {
+		fake.FlushLogsStub(guid)
+	}
+}
+
+func (fake *FakeBuilder) FlushLogsCallCount() int {
+	fake.flushLogsMutex.RLock()
+	defer fake.flushLogsMutex.RUnlock()
+	return len(fake.flushLogsArgsForCall)
+}
+
+func (fake *FakeBuilder) FlushLogsArgsForCall(i int) string {
+	fake.flushLogsMutex.RLock()
+	defer fake.flushLogsMutex.RUnlock()
+	return fake.flushLogsArgsForCall[i].guid
+}
+
 var _ builder.Builder = new(FakeBuilder)
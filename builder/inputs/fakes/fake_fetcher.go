@@ -10,12 +10,13 @@ import (
 )
 
 type FakeFetcher struct {
-	FetchStub        func([]turbine.Input, event.Emitter, <-chan struct{}) ([]inputs.FetchedInput, error)
+	FetchStub        func([]turbine.Input, event.Emitter, []string, <-chan struct{}) ([]inputs.FetchedInput, error)
 	fetchMutex       sync.RWMutex
 	fetchArgsForCall []struct {
 		arg1 []turbine.Input
 		arg2 event.Emitter
-		arg3 <-chan struct{}
+		arg3 []string
+		arg4 <-chan struct{}
 	}
 	fetchReturns struct {
 		result1 []inputs.FetchedInput
@@ -23,16 +24,17 @@ type FakeFetcher struct {
 	}
 }
 
-func (fake *FakeFetcher) Fetch(arg1 []turbine.Input, arg2 event.Emitter, arg3 <-chan struct{}) ([]inputs.FetchedInput, error) {
+func (fake *FakeFetcher) Fetch(arg1 []turbine.Input, arg2 event.Emitter, arg3 []string, arg4 <-chan struct{}) ([]inputs.FetchedInput, error) {
 	fake.fetchMutex.Lock()
 	fake.fetchArgsForCall = append(fake.fetchArgsForCall, struct {
 		arg1 []turbine.Input
 		arg2 event.Emitter
-		arg3 <-chan struct{}
-	}{arg1, arg2, arg3})
+		arg3 []string
+		arg4 <-chan struct{}
+	}{arg1, arg2, arg3, arg4})
 	fake.fetchMutex.Unlock()
 	if fake.FetchStub != nil {
-		return fake.FetchStub(arg1, arg2, arg3)
+		return fake.FetchStub(arg1, arg2, arg3, arg4)
 	} else {
 		return fake.fetchReturns.result1, fake.fetchReturns.result2
 	}
@@ -44,10 +46,10 @@ func (fake *FakeFetcher) FetchCallCount() int {
 	return len(fake.fetchArgsForCall)
 }
 
-func (fake *FakeFetcher) FetchArgsForCall(i int) ([]turbine.Input, event.Emitter, <-chan struct{}) {
+func (fake *FakeFetcher) FetchArgsForCall(i int) ([]turbine.Input, event.Emitter, []string, <-chan struct{}) {
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
-	return fake.fetchArgsForCall[i].arg1, fake.fetchArgsForCall[i].arg2, fake.fetchArgsForCall[i].arg3
+	return fake.fetchArgsForCall[i].arg1, fake.fetchArgsForCall[i].arg2, fake.fetchArgsForCall[i].arg3, fake.fetchArgsForCall[i].arg4
 }
 
 func (fake *FakeFetcher) FetchReturns(result1 []inputs.FetchedInput, result2 error) {
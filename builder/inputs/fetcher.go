@@ -1,7 +1,10 @@
 package inputs
 
 import (
+	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/event"
@@ -15,81 +18,138 @@ type FetchedInput struct {
 	Release func() error
 }
 
+// Fetcher fetches a build's inputs, emitting their output as events; the
+// given secrets are to be masked by the emitter, and so are not split across
+// events.
 type Fetcher interface {
-	Fetch([]turbine.Input, event.Emitter, <-chan struct{}) ([]FetchedInput, error)
+	Fetch([]turbine.Input, event.Emitter, []string, <-chan struct{}) ([]FetchedInput, error)
+}
+
+type InputFailure struct {
+	Name string
+	Err  error
+}
+
+// ErrFetchFailed names every input that failed, in the order they were
+// given. Inputs canceled because another one failed are not included.
+type ErrFetchFailed struct {
+	Failures []InputFailure
+}
+
+func (err ErrFetchFailed) Error() string {
+	msgs := make([]string, len(err.Failures))
+	for i, failure := range err.Failures {
+		msgs[i] = fmt.Sprintf("%s: %s", failure.Name, failure.Err)
+	}
+
+	return "failed to fetch inputs: " + strings.Join(msgs, "; ")
 }
 
 type parallelFetcher struct {
-	tracker resource.Tracker
+	tracker     resource.Tracker
+	maxParallel int
+	clock       event.Clock
 }
 
-func NewParallelFetcher(tracker resource.Tracker) Fetcher {
+// NewParallelFetcher returns a Fetcher that fetches up to maxParallel inputs
+// at a time. If maxParallel is 0 every input is fetched at once.
+func NewParallelFetcher(tracker resource.Tracker, maxParallel int, clock event.Clock) Fetcher {
 	return &parallelFetcher{
-		tracker: tracker,
+		tracker:     tracker,
+		maxParallel: maxParallel,
+		clock:       clock,
 	}
 }
 
-func (fetcher *parallelFetcher) Fetch(inputs []turbine.Input, emitter event.Emitter, abort <-chan struct{}) ([]FetchedInput, error) {
+func (fetcher *parallelFetcher) Fetch(inputs []turbine.Input, emitter event.Emitter, secrets []string, abort <-chan struct{}) ([]FetchedInput, error) {
 	fetchedInputs := make([]FetchedInput, len(inputs))
+	failures := make([]error, len(inputs))
+
+	// closed when the build is aborted or an input fails, so that the
+	// remaining fetches stop early
+	fetchAbort := make(chan struct{})
+	done := make(chan struct{})
+
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() { close(fetchAbort) })
+	}
+
+	go func() {
+		select {
+		case <-abort:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	defer close(done)
 
-	errResults := make(chan error, len(inputs))
+	parallelism := fetcher.maxParallel
+	if parallelism <= 0 || parallelism > len(inputs) {
+		parallelism = len(inputs)
+	}
+
+	slots := make(chan struct{}, parallelism)
 
-	initializedResources := make(chan resource.Resource, len(inputs))
+	wg := new(sync.WaitGroup)
 
 	for i, input := range inputs {
+		wg.Add(1)
+
 		go func(i int, input turbine.Input) {
-			eventLog := event.NewWriter(emitter, event.Origin{
-				Type: event.OriginTypeInput,
-				Name: input.Name,
-			})
+			defer wg.Done()
 
-			resource, err := fetcher.tracker.Init(input.Type, eventLog, abort)
-			if err != nil {
-				emitInputError(emitter, input, err)
-				errResults <- err
-				return
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-fetchAbort:
 			}
 
-			initializedResources <- resource
+			if isClosed(fetchAbort) {
+				// never started; only the build being aborted is worth reporting
+				if isClosed(abort) {
+					failures[i] = resource.ErrAborted
+				}
 
-			tarStream, computedInput, buildConfig, err := resource.In(input)
-			if err != nil {
-				emitInputError(emitter, input, err)
-				errResults <- err
 				return
 			}
 
-			emitter.EmitEvent(event.Input{Input: computedInput})
+			fetched, err := fetcher.fetch(input, emitter, secrets, fetchAbort)
+			if err != nil {
+				cancel()
+
+				if err == resource.ErrAborted && !isClosed(abort) {
+					// canceled because another input failed
+					return
+				}
+
+				emitInputError(emitter, input, err)
+				failures[i] = err
 
-			fetchedInputs[i] = FetchedInput{
-				Input:  computedInput,
-				Stream: tarStream,
-				Config: buildConfig,
-				Release: func() error {
-					return fetcher.tracker.Release(resource)
-				},
+				return
 			}
 
-			errResults <- nil
+			fetchedInputs[i] = fetched
 		}(i, input)
 	}
 
-	var fetchErr error
-	for i := 0; i < len(inputs); i++ {
-		err := <-errResults
+	wg.Wait()
+
+	var fetchErr ErrFetchFailed
+	for i, err := range failures {
 		if err != nil {
-			fetchErr = err
+			fetchErr.Failures = append(fetchErr.Failures, InputFailure{
+				Name: inputs[i].Name,
+				Err:  err,
+			})
 		}
 	}
 
-	if fetchErr != nil {
-	dance:
-		for {
-			select {
-			case res := <-initializedResources:
-				fetcher.tracker.Release(res)
-			default:
-				break dance
+	if len(fetchErr.Failures) > 0 {
+		for _, fetched := range fetchedInputs {
+			if fetched.Release != nil {
+				fetched.Release()
 			}
 		}
 
@@ -99,6 +159,66 @@ func (fetcher *parallelFetcher) Fetch(inputs []turbine.Input, emitter event.Emit
 	return fetchedInputs, nil
 }
 
+func (fetcher *parallelFetcher) fetch(input turbine.Input, emitter event.Emitter, secrets []string, abort <-chan struct{}) (FetchedInput, error) {
+	origin := event.Origin{
+		Type: event.OriginTypeInput,
+		Name: input.Name,
+	}
+
+	eventLog := event.NewWriter(emitter, origin, fetcher.clock, secrets)
+	progress := event.NewProgressWriter(emitter, origin)
+
+	var fetched resource.Resource
+	var tarStream io.Reader
+	var computedInput turbine.Input
+	var buildConfig turbine.Config
+
+	// each attempt gets a fresh container
+	err := resource.WithRetries(input.Retry, eventLog, abort, func() error {
+		res, err := fetcher.tracker.Init(input.Type, eventLog, progress, abort)
+		if err != nil {
+			return err
+		}
+
+		tarStream, computedInput, buildConfig, err = res.In(input)
+		if err != nil {
+			fetcher.tracker.Release(res)
+			return err
+		}
+
+		fetched = res
+
+		return nil
+	})
+
+	// emit the remaining logs before the input's outcome
+	eventLog.Close()
+
+	if err != nil {
+		return FetchedInput{}, err
+	}
+
+	emitter.EmitEvent(event.Input{Input: computedInput})
+
+	return FetchedInput{
+		Input:  computedInput,
+		Stream: tarStream,
+		Config: buildConfig,
+		Release: func() error {
+			return fetcher.tracker.Release(fetched)
+		},
+	}, nil
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
 func emitInputError(emitter event.Emitter, input turbine.Input, err error) {
 	emitter.EmitEvent(event.Error{
 		Message: err.Error(),
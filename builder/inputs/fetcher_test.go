@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -25,6 +26,7 @@ var _ = Describe("Inputs", func() {
 
 		inputs  []turbine.Input
 		emitter *efakes.FakeEmitter
+		clock   *efakes.FakeClock
 		events  *testlog.EventLog
 		abort   chan struct{}
 
@@ -37,18 +39,25 @@ var _ = Describe("Inputs", func() {
 	BeforeEach(func() {
 		tracker = new(rfakes.FakeTracker)
 
-		fetcher = NewParallelFetcher(tracker)
+		// flush output as soon as it's written
+		flushNow := make(chan time.Time)
+		close(flushNow)
+
+		clock = new(efakes.FakeClock)
+		clock.AfterReturns(flushNow)
+
+		fetcher = NewParallelFetcher(tracker, 0, clock)
 
 		inputs = []turbine.Input{
 			{
 				Name:     "first-input",
 				Resource: "first-resource",
-				Type:     "raw",
+				Type:     "first-type",
 			},
 			{
 				Name:     "second-input",
 				Resource: "second-resource",
-				Type:     "raw",
+				Type:     "second-type",
 			},
 		}
 
@@ -68,17 +77,21 @@ var _ = Describe("Inputs", func() {
 			resource1 = new(rfakes.FakeResource)
 			resource2 = new(rfakes.FakeResource)
 
-			resources := make(chan resource.Resource, 2)
-			resources <- resource1
-			resources <- resource2
-
-			tracker.InitStub = func(typ string, logs io.Writer, abort <-chan struct{}) (resource.Resource, error) {
-				return <-resources, nil
+			// each input has its own type, so map it to its resource
+			tracker.InitStub = func(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (resource.Resource, error) {
+				switch typ {
+				case "first-type":
+					return resource1, nil
+				case "second-type":
+					return resource2, nil
+				default:
+					panic("unknown type: " + typ)
+				}
 			}
 		})
 
 		JustBeforeEach(func() {
-			fetchedInputs, fetchErr = fetcher.Fetch(inputs, emitter, abort)
+			fetchedInputs, fetchErr = fetcher.Fetch(inputs, emitter, nil, abort)
 		})
 
 		Context("when each resource in action succeeds", func() {
@@ -125,7 +138,7 @@ var _ = Describe("Inputs", func() {
 				Ω(fetchedInputs[0].Input).Should(Equal(turbine.Input{
 					Name:     "first-input",
 					Resource: "first-resource",
-					Type:     "raw",
+					Type:     "first-type",
 					Version:  turbine.Version{"version": "1"},
 					Metadata: []turbine.MetadataField{{Name: "key", Value: "meta-1"}},
 				}))
@@ -138,7 +151,7 @@ var _ = Describe("Inputs", func() {
 				Ω(fetchedInputs[1].Input).Should(Equal(turbine.Input{
 					Name:     "second-input",
 					Resource: "second-resource",
-					Type:     "raw",
+					Type:     "second-type",
 					Version:  turbine.Version{"version": "2"},
 					Metadata: []turbine.MetadataField{{Name: "key", Value: "meta-2"}},
 				}))
@@ -154,7 +167,7 @@ var _ = Describe("Inputs", func() {
 					Input: turbine.Input{
 						Name:     "first-input",
 						Resource: "first-resource",
-						Type:     "raw",
+						Type:     "first-type",
 						Version:  turbine.Version{"version": "1"},
 						Metadata: []turbine.MetadataField{{Name: "key", Value: "meta-1"}},
 					},
@@ -164,7 +177,7 @@ var _ = Describe("Inputs", func() {
 					Input: turbine.Input{
 						Name:     "second-input",
 						Resource: "second-resource",
-						Type:     "raw",
+						Type:     "second-type",
 						Version:  turbine.Version{"version": "2"},
 						Metadata: []turbine.MetadataField{{Name: "key", Value: "meta-2"}},
 					},
@@ -209,7 +222,7 @@ var _ = Describe("Inputs", func() {
 
 			Context("when the inputs emit logs", func() {
 				BeforeEach(func() {
-					tracker.InitStub = func(typ string, logs io.Writer, abort <-chan struct{}) (resource.Resource, error) {
+					tracker.InitStub = func(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (resource.Resource, error) {
 						go func() {
 							defer GinkgoRecover()
 
@@ -222,7 +235,7 @@ var _ = Describe("Inputs", func() {
 				})
 
 				It("emits a build log event", func() {
-					Eventually(events.Sent).Should(ContainElement(event.Log{
+					Eventually(events.Logs).Should(ContainElement(event.Log{
 						Payload: "hello from the resource",
 						Origin: event.Origin{
 							Type: event.OriginTypeInput,
@@ -234,12 +247,84 @@ var _ = Describe("Inputs", func() {
 
 			Context("when fetching is aborted", func() {
 				BeforeEach(func() {
-					close(abort)
+					resource1.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+						close(abort)
+						return nil, turbine.Input{}, turbine.Config{}, resource.ErrAborted
+					}
+
+					resource2.InStub = nil
 				})
 
 				It("aborts all resource activity", func() {
-					_, _, resourceAbort := tracker.InitArgsForCall(0)
-					Ω(resourceAbort).Should(BeClosed())
+					for i := 0; i < tracker.InitCallCount(); i++ {
+						_, _, _, resourceAbort := tracker.InitArgsForCall(i)
+						Ω(resourceAbort).Should(BeClosed())
+					}
+				})
+
+				It("reports the aborted input as failed", func() {
+					Ω(fetchErr).Should(HaveOccurred())
+
+					failures := fetchErr.(ErrFetchFailed).Failures
+					Ω(failures).ShouldNot(BeEmpty())
+					Ω(failures[0]).Should(Equal(InputFailure{
+						Name: "first-input",
+						Err:  resource.ErrAborted,
+					}))
+				})
+			})
+		})
+
+		Context("with a parallelism cap", func() {
+			var running, maxRunning int
+			var runningLock sync.Mutex
+
+			BeforeEach(func() {
+				fetcher = NewParallelFetcher(tracker, 1, clock)
+
+				running = 0
+				maxRunning = 0
+
+				inStub := func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					runningLock.Lock()
+					running++
+					if running > maxRunning {
+						maxRunning = running
+					}
+					runningLock.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					runningLock.Lock()
+					running--
+					runningLock.Unlock()
+
+					return new(bytes.Buffer), input, turbine.Config{}, nil
+				}
+
+				resource1.InStub = inStub
+				resource2.InStub = inStub
+			})
+
+			It("fetches no more than that many inputs at once", func() {
+				Ω(fetchErr).ShouldNot(HaveOccurred())
+				Ω(fetchedInputs).Should(HaveLen(2))
+
+				Ω(maxRunning).Should(Equal(1))
+			})
+
+			Context("when an input fails", func() {
+				disaster := errors.New("oh no!")
+
+				BeforeEach(func() {
+					resource1.InReturns(nil, turbine.Input{}, turbine.Config{}, disaster)
+					resource2.InReturns(nil, turbine.Input{}, turbine.Config{}, disaster)
+				})
+
+				It("does not start the inputs that have not been fetched yet", func() {
+					Ω(tracker.InitCallCount()).Should(Equal(1))
+
+					Ω(fetchErr.(ErrFetchFailed).Failures).Should(HaveLen(1))
 				})
 			})
 		})
@@ -248,21 +333,33 @@ var _ = Describe("Inputs", func() {
 			disaster := errors.New("oh no!")
 
 			BeforeEach(func() {
-				resources := make(chan resource.Resource, 1)
-				resources <- resource1
-
-				tracker.InitStub = func(typ string, logs io.Writer, abort <-chan struct{}) (resource.Resource, error) {
-					select {
-					case res := <-resources:
-						return res, nil
-					default:
-						return nil, disaster
+				firstFetched := make(chan struct{})
+
+				resource1.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					close(firstFetched)
+					return new(bytes.Buffer), input, turbine.Config{}, nil
+				}
+
+				tracker.InitStub = func(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (resource.Resource, error) {
+					if typ == "first-type" {
+						return resource1, nil
 					}
+
+					// fail only once the first input is fetched, so it isn't canceled
+					<-firstFetched
+
+					return nil, disaster
 				}
 			})
 
-			It("returns the error", func() {
-				Ω(fetchErr).Should(Equal(disaster))
+			It("returns an error naming the input", func() {
+				Ω(fetchErr).Should(Equal(ErrFetchFailed{
+					Failures: []InputFailure{
+						{Name: "second-input", Err: disaster},
+					},
+				}))
+
+				Ω(fetchErr.Error()).Should(Equal("failed to fetch inputs: second-input: oh no!"))
 			})
 
 			It("releases all successfully-initialized resources", func() {
@@ -285,17 +382,36 @@ var _ = Describe("Inputs", func() {
 			disaster := errors.New("oh no!")
 
 			BeforeEach(func() {
-				resource1.InReturns(nil, turbine.Input{}, turbine.Config{}, disaster)
+				secondFetched := make(chan struct{})
+
+				resource2.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					close(secondFetched)
+					return new(bytes.Buffer), input, turbine.Config{}, nil
+				}
+
+				resource1.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					<-secondFetched
+					return nil, turbine.Input{}, turbine.Config{}, disaster
+				}
 			})
 
-			It("returns the error", func() {
-				Ω(fetchErr).Should(Equal(disaster))
+			It("returns an error naming the input", func() {
+				Ω(fetchErr).Should(Equal(ErrFetchFailed{
+					Failures: []InputFailure{
+						{Name: "first-input", Err: disaster},
+					},
+				}))
 			})
 
 			It("releases all resources", func() {
 				Ω(tracker.ReleaseCallCount()).Should(Equal(2))
-				Ω(tracker.ReleaseArgsForCall(0)).Should(Equal(resource1))
-				Ω(tracker.ReleaseArgsForCall(1)).Should(Equal(resource2))
+
+				allReleased := []resource.Resource{
+					tracker.ReleaseArgsForCall(0),
+					tracker.ReleaseArgsForCall(1),
+				}
+
+				Ω(allReleased).Should(ConsistOf(resource1, resource2))
 			})
 
 			It("emits an error event", func() {
@@ -308,5 +424,84 @@ var _ = Describe("Inputs", func() {
 				}))
 			})
 		})
+
+		Context("when another input is still being fetched when one fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				secondStarted := make(chan struct{})
+
+				resource1.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					<-secondStarted
+					return nil, turbine.Input{}, turbine.Config{}, disaster
+				}
+
+				resource2.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					close(secondStarted)
+
+					var resourceAbort <-chan struct{}
+					for i := 0; i < tracker.InitCallCount(); i++ {
+						if typ, _, _, a := tracker.InitArgsForCall(i); typ == "second-type" {
+							resourceAbort = a
+						}
+					}
+
+					<-resourceAbort
+
+					return nil, turbine.Input{}, turbine.Config{}, resource.ErrAborted
+				}
+			})
+
+			It("cancels it and waits for it to clean up", func() {
+				Ω(resource2.InCallCount()).Should(Equal(1))
+				Ω(tracker.ReleaseCallCount()).Should(Equal(2))
+			})
+
+			It("does not report it as failed", func() {
+				Ω(fetchErr).Should(Equal(ErrFetchFailed{
+					Failures: []InputFailure{
+						{Name: "first-input", Err: disaster},
+					},
+				}))
+
+				Ω(events.Sent()).ShouldNot(ContainElement(event.Error{
+					Message: resource.ErrAborted.Error(),
+					Origin: event.Origin{
+						Type: event.OriginTypeInput,
+						Name: "second-input",
+					},
+				}))
+			})
+		})
+
+		Context("when every input fails", func() {
+			BeforeEach(func() {
+				sync := new(sync.WaitGroup)
+				sync.Add(2)
+
+				resource1.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					sync.Done()
+					sync.Wait()
+					return nil, turbine.Input{}, turbine.Config{}, errors.New("first failed")
+				}
+
+				resource2.InStub = func(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+					sync.Done()
+					sync.Wait()
+					return nil, turbine.Input{}, turbine.Config{}, errors.New("second failed")
+				}
+			})
+
+			It("names each of them in order", func() {
+				Ω(fetchErr).Should(Equal(ErrFetchFailed{
+					Failures: []InputFailure{
+						{Name: "first-input", Err: errors.New("first failed")},
+						{Name: "second-input", Err: errors.New("second failed")},
+					},
+				}))
+
+				Ω(fetchErr.Error()).Should(Equal("failed to fetch inputs: first-input: first failed; second-input: second failed"))
+			})
+		})
 	})
 })
@@ -4,20 +4,21 @@ package fakes
 import (
 	"sync"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/builder/outputs"
 	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/runtime"
 )
 
 type FakePerformer struct {
-	PerformOutputsStub        func(garden.Container, []turbine.Output, event.Emitter, <-chan struct{}) ([]turbine.Output, error)
+	PerformOutputsStub        func(runtime.Container, []turbine.Output, event.Emitter, []string, <-chan struct{}) ([]turbine.Output, error)
 	performOutputsMutex       sync.RWMutex
 	performOutputsArgsForCall []struct {
-		arg1 garden.Container
+		arg1 runtime.Container
 		arg2 []turbine.Output
 		arg3 event.Emitter
-		arg4 <-chan struct{}
+		arg4 []string
+		arg5 <-chan struct{}
 	}
 	performOutputsReturns struct {
 		result1 []turbine.Output
@@ -25,17 +26,18 @@ type FakePerformer struct {
 	}
 }
 
-func (fake *FakePerformer) PerformOutputs(arg1 garden.Container, arg2 []turbine.Output, arg3 event.Emitter, arg4 <-chan struct{}) ([]turbine.Output, error) {
+func (fake *FakePerformer) PerformOutputs(arg1 runtime.Container, arg2 []turbine.Output, arg3 event.Emitter, arg4 []string, arg5 <-chan struct{}) ([]turbine.Output, error) {
 	fake.performOutputsMutex.Lock()
 	fake.performOutputsArgsForCall = append(fake.performOutputsArgsForCall, struct {
-		arg1 garden.Container
+		arg1 runtime.Container
 		arg2 []turbine.Output
 		arg3 event.Emitter
-		arg4 <-chan struct{}
-	}{arg1, arg2, arg3, arg4})
+		arg4 []string
+		arg5 <-chan struct{}
+	}{arg1, arg2, arg3, arg4, arg5})
 	fake.performOutputsMutex.Unlock()
 	if fake.PerformOutputsStub != nil {
-		return fake.PerformOutputsStub(arg1, arg2, arg3, arg4)
+		return fake.PerformOutputsStub(arg1, arg2, arg3, arg4, arg5)
 	} else {
 		return fake.performOutputsReturns.result1, fake.performOutputsReturns.result2
 	}
@@ -47,10 +49,10 @@ func (fake *FakePerformer) PerformOutputsCallCount() int {
 	return len(fake.performOutputsArgsForCall)
 }
 
-func (fake *FakePerformer) PerformOutputsArgsForCall(i int) (garden.Container, []turbine.Output, event.Emitter, <-chan struct{}) {
+func (fake *FakePerformer) PerformOutputsArgsForCall(i int) (runtime.Container, []turbine.Output, event.Emitter, []string, <-chan struct{}) {
 	fake.performOutputsMutex.RLock()
 	defer fake.performOutputsMutex.RUnlock()
-	return fake.performOutputsArgsForCall[i].arg1, fake.performOutputsArgsForCall[i].arg2, fake.performOutputsArgsForCall[i].arg3, fake.performOutputsArgsForCall[i].arg4
+	return fake.performOutputsArgsForCall[i].arg1, fake.performOutputsArgsForCall[i].arg2, fake.performOutputsArgsForCall[i].arg3, fake.performOutputsArgsForCall[i].arg4, fake.performOutputsArgsForCall[i].arg5
 }
 
 func (fake *FakePerformer) PerformOutputsReturns(result1 []turbine.Output, result2 error) {
@@ -1,85 +1,180 @@
 package outputs
 
 import (
-	garden "github.com/cloudfoundry-incubator/garden/api"
+	"fmt"
+
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/event"
 	"github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/runtime"
 )
 
+// Performer performs a build's outputs, emitting their output as events;
+// the given secrets are to be masked by the emitter, and so are not split
+// across events.
 type Performer interface {
-	PerformOutputs(garden.Container, []turbine.Output, event.Emitter, <-chan struct{}) ([]turbine.Output, error)
+	PerformOutputs(runtime.Container, []turbine.Output, event.Emitter, []string, <-chan struct{}) ([]turbine.Output, error)
+}
+
+// ErrDependencyNotPerformed is returned for an output that was skipped
+// because an output it depends on failed or was not performed.
+type ErrDependencyNotPerformed struct {
+	Output     string
+	Dependency string
+	Reason     string
 }
 
-func NewParallelPerformer(tracker resource.Tracker) Performer {
-	return parallelPerformer{tracker: tracker}
+func (err ErrDependencyNotPerformed) Error() string {
+	return fmt.Sprintf("skipped output '%s': depends on output '%s', which %s", err.Output, err.Dependency, err.Reason)
+}
+
+func NewParallelPerformer(tracker resource.Tracker, clock event.Clock) Performer {
+	return parallelPerformer{
+		tracker: tracker,
+		clock:   clock,
+	}
 }
 
 type parallelPerformer struct {
 	tracker resource.Tracker
+	clock   event.Clock
+}
+
+type outputResult struct {
+	output turbine.Output
+	err    error
+	done   chan struct{}
 }
 
 func (p parallelPerformer) PerformOutputs(
-	container garden.Container,
+	container runtime.Container,
 	outputs []turbine.Output,
 	emitter event.Emitter,
+	secrets []string,
 	abort <-chan struct{},
 ) ([]turbine.Output, error) {
-	resultingOutputs := make([]turbine.Output, len(outputs))
+	// outputs run in parallel unless they depend on one another, in which
+	// case they wait for their dependencies to be performed
+	results := make(map[string]*outputResult, len(outputs))
+	for _, output := range outputs {
+		results[output.Name] = &outputResult{done: make(chan struct{})}
+	}
 
-	errResults := make(chan error, len(outputs))
+	for _, output := range outputs {
+		go func(output turbine.Output) {
+			result := results[output.Name]
+			defer close(result.done)
 
-	for i, output := range outputs {
-		go func(i int, output turbine.Output) {
-			streamOut, err := container.StreamOut("/tmp/build/src/")
-			if err != nil {
-				emitOutputError(emitter, output, err)
-				errResults <- err
+			result.output, result.err = p.performOutput(container, output, results, emitter, secrets, abort)
+			if result.err != nil {
+				emitOutputError(emitter, output, result.err)
 				return
 			}
 
-			eventLog := event.NewWriter(emitter, event.Origin{
-				Type: event.OriginTypeOutput,
-				Name: output.Name,
-			})
+			emitter.EmitEvent(event.Output{Output: result.output})
+		}(output)
+	}
 
-			resource, err := p.tracker.Init(output.Type, eventLog, abort)
-			if err != nil {
-				emitOutputError(emitter, output, err)
-				errResults <- err
-				return
+	resultingOutputs := make([]turbine.Output, len(outputs))
+
+	var outputErr error
+	for i, output := range outputs {
+		result := results[output.Name]
+		<-result.done
+
+		if result.err != nil {
+			// prefer the error that caused dependent outputs to be skipped
+			_, skipped := result.err.(ErrDependencyNotPerformed)
+			if outputErr == nil || !skipped {
+				outputErr = result.err
 			}
+		}
 
-			defer p.tracker.Release(resource)
+		resultingOutputs[i] = result.output
+	}
 
-			computedOutput, err := resource.Out(streamOut, output)
-			if err != nil {
-				emitOutputError(emitter, output, err)
-				errResults <- err
-				return
+	if outputErr != nil {
+		return nil, outputErr
+	}
+
+	return resultingOutputs, nil
+}
+
+func (p parallelPerformer) performOutput(
+	container runtime.Container,
+	output turbine.Output,
+	results map[string]*outputResult,
+	emitter event.Emitter,
+	secrets []string,
+	abort <-chan struct{},
+) (turbine.Output, error) {
+	dependencies := make([]turbine.Output, 0, len(output.DependsOn))
+	for _, name := range output.DependsOn {
+		dependency, found := results[name]
+		if !found {
+			return turbine.Output{}, ErrDependencyNotPerformed{
+				Output:     output.Name,
+				Dependency: name,
+				Reason:     "was not performed",
 			}
+		}
 
-			emitter.EmitEvent(event.Output{Output: computedOutput})
+		<-dependency.done
 
-			resultingOutputs[i] = computedOutput
+		if dependency.err != nil {
+			return turbine.Output{}, ErrDependencyNotPerformed{
+				Output:     output.Name,
+				Dependency: name,
+				Reason:     "failed",
+			}
+		}
 
-			errResults <- nil
-		}(i, output)
+		dependencies = append(dependencies, dependency.output)
 	}
 
-	var outputErr error
-	for i := 0; i < len(outputs); i++ {
-		err := <-errResults
+	output, err := output.Interpolate(dependencies)
+	if err != nil {
+		return turbine.Output{}, err
+	}
+
+	origin := event.Origin{
+		Type: event.OriginTypeOutput,
+		Name: output.Name,
+	}
+
+	eventLog := event.NewWriter(emitter, origin, p.clock, secrets)
+	progress := event.NewProgressWriter(emitter, origin)
+
+	var computedOutput turbine.Output
+
+	// each attempt gets a fresh container and stream of the sources
+	err = resource.WithRetries(output.Retry, eventLog, abort, func() error {
+		streamOut, err := container.StreamOut("/tmp/build/src/")
 		if err != nil {
-			outputErr = err
+			return err
 		}
-	}
 
-	if outputErr != nil {
-		return nil, outputErr
+		defer streamOut.Close()
+
+		res, err := p.tracker.Init(output.Type, eventLog, progress, abort)
+		if err != nil {
+			return err
+		}
+
+		defer p.tracker.Release(res)
+
+		computedOutput, err = res.Out(streamOut, output)
+		return err
+	})
+
+	// emit the remaining logs before the output's outcome
+	eventLog.Close()
+
+	if err != nil {
+		return turbine.Output{}, err
 	}
 
-	return resultingOutputs, nil
+	return computedOutput, nil
 }
 
 func emitOutputError(emitter event.Emitter, output turbine.Output, err error) {
@@ -7,8 +7,6 @@ import (
 	"io/ioutil"
 	"sync"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
-	"github.com/cloudfoundry-incubator/garden/client/fake_api_client"
 	"github.com/concourse/turbine"
 	. "github.com/concourse/turbine/builder/outputs"
 	"github.com/concourse/turbine/event"
@@ -16,19 +14,19 @@ import (
 	"github.com/concourse/turbine/event/testlog"
 	"github.com/concourse/turbine/resource"
 	rfakes "github.com/concourse/turbine/resource/fakes"
+	rtfakes "github.com/concourse/turbine/runtime/fakes"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("Performer", func() {
 	var (
-		gardenClient *fake_api_client.FakeClient
-
 		tracker   *rfakes.FakeTracker
 		performer Performer
 
-		container        garden.Container
+		container        *rtfakes.FakeContainer
 		outputsToPerform []turbine.Output
 		emitter          *efakes.FakeEmitter
 		events           *testlog.EventLog
@@ -42,14 +40,7 @@ var _ = Describe("Performer", func() {
 	)
 
 	BeforeEach(func() {
-		var err error
-
-		gardenClient = fake_api_client.New()
-
-		gardenClient.Connection.CreateReturns("the-performing-container", nil)
-
-		container, err = gardenClient.Create(garden.ContainerSpec{})
-		Ω(err).ShouldNot(HaveOccurred())
+		container = new(rtfakes.FakeContainer)
 
 		resource1 = new(rfakes.FakeResource)
 		resource2 = new(rfakes.FakeResource)
@@ -59,7 +50,7 @@ var _ = Describe("Performer", func() {
 		resources <- resource2
 
 		tracker = new(rfakes.FakeTracker)
-		tracker.InitStub = func(typ string, logs io.Writer, abort <-chan struct{}) (resource.Resource, error) {
+		tracker.InitStub = func(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (resource.Resource, error) {
 			select {
 			case r := <-resources:
 				return r, nil
@@ -89,12 +80,12 @@ var _ = Describe("Performer", func() {
 		events = &testlog.EventLog{}
 		emitter.EmitEventStub = events.Add
 
-		performer = NewParallelPerformer(tracker)
+		performer = NewParallelPerformer(tracker, new(efakes.FakeClock))
 	})
 
 	JustBeforeEach(func() {
 		abort = make(chan struct{})
-		performedOutputs, performErr = performer.PerformOutputs(container, outputsToPerform, emitter, abort)
+		performedOutputs, performErr = performer.PerformOutputs(container, outputsToPerform, emitter, nil, abort)
 	})
 
 	performedOutput := func(output turbine.Output) turbine.Output {
@@ -105,7 +96,7 @@ var _ = Describe("Performer", func() {
 
 	Context("when streaming out succeeds", func() {
 		BeforeEach(func() {
-			gardenClient.Connection.StreamOutStub = func(handle string, srcPath string) (io.ReadCloser, error) {
+			container.StreamOutStub = func(srcPath string) (io.ReadCloser, error) {
 				return ioutil.NopCloser(bytes.NewBufferString("streamed-out")), nil
 			}
 		})
@@ -166,10 +157,10 @@ var _ = Describe("Performer", func() {
 				}
 
 				Ω(performedOutputs).Should(ContainElement(monkeyResult))
-				Ω(events.Sent()).Should(ContainElement(event.Output{monkeyResult}))
+				Ω(events.Sent()).Should(ContainElement(event.Output{Output: monkeyResult}))
 
 				Ω(performedOutputs).Should(ContainElement(bananaResult))
-				Ω(events.Sent()).Should(ContainElement(event.Output{bananaResult}))
+				Ω(events.Sent()).Should(ContainElement(event.Output{Output: bananaResult}))
 			})
 
 			It("releases each resource", func() {
@@ -210,7 +201,7 @@ var _ = Describe("Performer", func() {
 
 			It("does not emit a bogus output event", func() {
 				Ω(events.Sent()).ShouldNot(ContainElement(event.Output{
-					turbine.Output{Name: "failed-output"},
+					Output: turbine.Output{Name: "failed-output"},
 				}))
 			})
 
@@ -227,6 +218,150 @@ var _ = Describe("Performer", func() {
 			})
 		})
 
+		Context("when an output's script fails and its policy allows a retry", func() {
+			BeforeEach(func() {
+				outputsToPerform = outputsToPerform[:1]
+				outputsToPerform[0].Retry = &turbine.RetryPolicy{Attempts: 2}
+
+				resource1.OutReturns(turbine.Output{}, resource.ErrResourceScriptFailed{
+					Path:       "/opt/resource/out",
+					ExitStatus: 1,
+				})
+
+				resource2.OutStub = func(src io.Reader, output turbine.Output) (turbine.Output, error) {
+					return performedOutput(output), nil
+				}
+			})
+
+			It("performs the output again with a fresh resource", func() {
+				Ω(performErr).ShouldNot(HaveOccurred())
+				Ω(performedOutputs).Should(Equal([]turbine.Output{
+					performedOutput(outputsToPerform[0]),
+				}))
+
+				Ω(tracker.InitCallCount()).Should(Equal(2))
+				Ω(resource1.OutCallCount()).Should(Equal(1))
+				Ω(resource2.OutCallCount()).Should(Equal(1))
+			})
+
+			It("streams out the source again for the retry", func() {
+				Ω(container.StreamOutCallCount()).Should(Equal(2))
+			})
+
+			Context("with the streams tracked", func() {
+				var streams []*gbytes.Buffer
+
+				BeforeEach(func() {
+					streams = nil
+
+					container.StreamOutStub = func(srcPath string) (io.ReadCloser, error) {
+						stream := gbytes.BufferWithBytes([]byte("streamed-out"))
+						streams = append(streams, stream)
+						return stream, nil
+					}
+				})
+
+				It("closes each attempt's stream", func() {
+					Ω(streams).Should(HaveLen(2))
+					Ω(streams[0].Closed()).Should(BeTrue())
+					Ω(streams[1].Closed()).Should(BeTrue())
+				})
+			})
+
+			It("releases both resources", func() {
+				Ω(tracker.ReleaseCallCount()).Should(Equal(2))
+				Ω(tracker.ReleaseArgsForCall(0)).Should(Equal(resource1))
+				Ω(tracker.ReleaseArgsForCall(1)).Should(Equal(resource2))
+			})
+
+			It("logs the retry", func() {
+				Ω(events.Logs()).Should(ContainElement(event.Log{
+					Payload: "/opt/resource/out exited with status 1 (attempt 1 of 2); retrying in 0s\n",
+					Origin: event.Origin{
+						Type: event.OriginTypeOutput,
+						Name: "banana",
+					},
+				}))
+			})
+		})
+
+		Context("when an output depends on another", func() {
+			BeforeEach(func() {
+				outputsToPerform[1].DependsOn = []string{"banana"}
+				outputsToPerform[1].Params = turbine.Params{"key": "after-((outputs.banana.version.version))"}
+			})
+
+			Context("and the dependency succeeds", func() {
+				BeforeEach(func() {
+					bananaPerformed := make(chan struct{})
+
+					resource1.OutStub = func(src io.Reader, output turbine.Output) (turbine.Output, error) {
+						if output.Name == "banana" {
+							defer close(bananaPerformed)
+						} else {
+							<-bananaPerformed
+						}
+
+						return performedOutput(output), nil
+					}
+
+					resource2.OutStub = resource1.OutStub
+				})
+
+				It("performs it after its dependency, with the dependency's version", func() {
+					Ω(performErr).ShouldNot(HaveOccurred())
+
+					monkey := performedOutput(outputsToPerform[1])
+					monkey.Params = turbine.Params{"key": "after-banana-performed"}
+
+					Ω(performedOutputs).Should(Equal([]turbine.Output{
+						performedOutput(outputsToPerform[0]),
+						monkey,
+					}))
+				})
+			})
+
+			Context("and the dependency fails", func() {
+				disaster := errors.New("oh no!")
+
+				BeforeEach(func() {
+					resource1.OutReturns(turbine.Output{}, disaster)
+				})
+
+				It("returns the dependency's error", func() {
+					Ω(performErr).Should(Equal(disaster))
+				})
+
+				It("skips it with an error event", func() {
+					Ω(tracker.InitCallCount()).Should(Equal(1))
+
+					Ω(events.Sent()).Should(ContainElement(event.Error{
+						Message: "skipped output 'monkey': depends on output 'banana', which failed",
+						Origin: event.Origin{
+							Type: event.OriginTypeOutput,
+							Name: "monkey",
+						},
+					}))
+				})
+			})
+
+			Context("and the dependency is not being performed", func() {
+				BeforeEach(func() {
+					outputsToPerform = outputsToPerform[1:]
+				})
+
+				It("skips it with an error", func() {
+					Ω(performErr).Should(Equal(ErrDependencyNotPerformed{
+						Output:     "monkey",
+						Dependency: "banana",
+						Reason:     "was not performed",
+					}))
+
+					Ω(tracker.InitCallCount()).Should(BeZero())
+				})
+			})
+		})
+
 		Describe("when the outputs emit logs", func() {
 			BeforeEach(func() {
 				for i, resource := range []*rfakes.FakeResource{resource1, resource2} {
@@ -235,7 +370,7 @@ var _ = Describe("Performer", func() {
 					resource.OutStub = func(src io.Reader, output turbine.Output) (turbine.Output, error) {
 						defer GinkgoRecover()
 
-						_, logs, _ := tracker.InitArgsForCall(idx)
+						_, logs, _, _ := tracker.InitArgsForCall(idx)
 
 						Ω(logs).ShouldNot(BeNil())
 						logs.Write([]byte("hello from outputter"))
@@ -246,7 +381,7 @@ var _ = Describe("Performer", func() {
 			})
 
 			It("emits output events", func() {
-				Eventually(events.Sent).Should(ContainElement(event.Log{
+				Eventually(events.Logs).Should(ContainElement(event.Log{
 					Payload: "hello from outputter",
 					Origin: event.Origin{
 						Type: event.OriginTypeOutput,
@@ -254,7 +389,7 @@ var _ = Describe("Performer", func() {
 					},
 				}))
 
-				Eventually(events.Sent).Should(ContainElement(event.Log{
+				Eventually(events.Logs).Should(ContainElement(event.Log{
 					Payload: "hello from outputter",
 					Origin: event.Origin{
 						Type: event.OriginTypeOutput,
@@ -280,7 +415,7 @@ var _ = Describe("Performer", func() {
 
 				close(abort)
 
-				_, _, resourceAbort := tracker.InitArgsForCall(0)
+				_, _, _, resourceAbort := tracker.InitArgsForCall(0)
 				Ω(resourceAbort).Should(BeClosed())
 			})
 		})
@@ -290,6 +425,10 @@ var _ = Describe("Performer", func() {
 		disaster := errors.New("oh no!")
 
 		BeforeEach(func() {
+			container.StreamOutStub = func(srcPath string) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewBufferString("streamed-out")), nil
+			}
+
 			tracker.InitReturns(nil, disaster)
 		})
 
@@ -320,7 +459,7 @@ var _ = Describe("Performer", func() {
 		disaster := errors.New("oh no!")
 
 		BeforeEach(func() {
-			gardenClient.Connection.StreamOutReturns(nil, disaster)
+			container.StreamOutReturns(nil, disaster)
 		})
 
 		It("returns the error", func() {
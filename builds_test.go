@@ -1,6 +1,9 @@
 package turbine_test
 
 import (
+	"encoding/json"
+	"time"
+
 	. "github.com/concourse/turbine"
 
 	. "github.com/onsi/ginkgo"
@@ -31,6 +34,24 @@ var _ = Describe("Config", func() {
 			}))
 		})
 
+		It("merges secret params", func() {
+			Ω(Config{
+				SecretParams: map[string]string{
+					"FOO": "1",
+				},
+			}.Merge(Config{
+				SecretParams: map[string]string{
+					"FOO": "2",
+					"BAR": "3",
+				},
+			})).Should(Equal(Config{
+				SecretParams: map[string]string{
+					"FOO": "2",
+					"BAR": "3",
+				},
+			}))
+		})
+
 		It("overrides the image", func() {
 			Ω(Config{
 				Image: "some-image",
@@ -105,4 +126,404 @@ var _ = Describe("Config", func() {
 			}))
 		})
 	})
+
+	Describe("redacting", func() {
+		It("omits secret params while preserving other properties", func() {
+			Ω(Build{
+				Guid: "some-guid",
+				Config: Config{
+					Image: "some-image",
+					Params: map[string]string{
+						"FOO": "1",
+					},
+					SecretParams: map[string]string{
+						"TOKEN": "s3cr3t",
+					},
+				},
+			}.Redacted()).Should(Equal(Build{
+				Guid: "some-guid",
+				Config: Config{
+					Image: "some-image",
+					Params: map[string]string{
+						"FOO": "1",
+					},
+				},
+			}))
+		})
+	})
+
+	Describe("secrets", func() {
+		It("returns the non-empty secret param values, longest first", func() {
+			Ω(Config{
+				Params: map[string]string{
+					"FOO": "not-secret",
+				},
+				SecretParams: map[string]string{
+					"SHORT": "abc",
+					"LONG":  "abcdef",
+					"EMPTY": "",
+				},
+			}.Secrets()).Should(Equal([]string{"abcdef", "abc"}))
+		})
+	})
+
+	Describe("interpolating", func() {
+		var inputs []Input
+
+		BeforeEach(func() {
+			inputs = []Input{
+				{
+					Name:    "repo",
+					Version: Version{"ref": "abc123", "number": float64(42)},
+					Metadata: []MetadataField{
+						{Name: "author", Value: "some-author"},
+					},
+				},
+			}
+		})
+
+		It("resolves input versions and metadata in params, secret params, and run args", func() {
+			Ω(Config{
+				Image: "some-image",
+				Params: map[string]string{
+					"REF":    "((inputs.repo.version.ref))",
+					"NUMBER": "#((inputs.repo.version.number))",
+					"OTHER":  "((not-an-input-reference))",
+				},
+				SecretParams: map[string]string{
+					"AUTHOR": "((inputs.repo.metadata.author))",
+				},
+				Run: RunConfig{
+					Path: "some-path",
+					Args: []string{"((inputs.repo.version.ref))-((inputs.repo.metadata.author))"},
+				},
+			}.Interpolate(inputs)).Should(Equal(Config{
+				Image: "some-image",
+				Params: map[string]string{
+					"REF":    "abc123",
+					"NUMBER": "#42",
+					"OTHER":  "((not-an-input-reference))",
+				},
+				SecretParams: map[string]string{
+					"AUTHOR": "some-author",
+				},
+				Run: RunConfig{
+					Path: "some-path",
+					Args: []string{"abc123-some-author"},
+				},
+			}))
+		})
+
+		It("fails on references to unknown inputs", func() {
+			_, err := Config{
+				Params: map[string]string{"FOO": "((inputs.bogus.version.ref))"},
+			}.Interpolate(inputs)
+			Ω(err).Should(Equal(UnresolvedReferenceError{"inputs.bogus.version.ref", "unknown input"}))
+		})
+
+		It("fails on references to missing version keys", func() {
+			_, err := Config{
+				Params: map[string]string{"FOO": "((inputs.repo.version.bogus))"},
+			}.Interpolate(inputs)
+			Ω(err).Should(Equal(UnresolvedReferenceError{"inputs.repo.version.bogus", "input version has no such key"}))
+		})
+
+		It("fails on references to missing metadata fields", func() {
+			_, err := Config{
+				Run: RunConfig{Args: []string{"((inputs.repo.metadata.bogus))"}},
+			}.Interpolate(inputs)
+			Ω(err).Should(Equal(UnresolvedReferenceError{"inputs.repo.metadata.bogus", "input metadata has no such field"}))
+		})
+
+		It("fails on malformed references", func() {
+			_, err := Config{
+				Params: map[string]string{"FOO": "((inputs.repo.ref))"},
+			}.Interpolate(inputs)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("interpolating an output", func() {
+		var performed []Output
+
+		BeforeEach(func() {
+			performed = []Output{
+				{
+					Name:    "bump",
+					Version: Version{"number": "1.2.3"},
+					Metadata: []MetadataField{
+						{Name: "bumped", Value: "minor"},
+					},
+				},
+			}
+		})
+
+		It("resolves performed outputs' versions and metadata in params and source", func() {
+			Ω(Output{
+				Name: "tag",
+				Params: Params{
+					"tag":   "v((outputs.bump.version.number))",
+					"notes": []interface{}{"bumped ((outputs.bump.metadata.bumped))", float64(1)},
+					"other": "((inputs.repo.version.ref))",
+				},
+				Source: Source{
+					"branch": map[string]interface{}{"name": "release-((outputs.bump.version.number))"},
+				},
+			}.Interpolate(performed)).Should(Equal(Output{
+				Name: "tag",
+				Params: Params{
+					"tag":   "v1.2.3",
+					"notes": []interface{}{"bumped minor", float64(1)},
+					"other": "((inputs.repo.version.ref))",
+				},
+				Source: Source{
+					"branch": map[string]interface{}{"name": "release-1.2.3"},
+				},
+			}))
+		})
+
+		It("fails on references to outputs that were not performed", func() {
+			_, err := Output{
+				Params: Params{"tag": "((outputs.bogus.version.number))"},
+			}.Interpolate(performed)
+			Ω(err).Should(Equal(UnresolvedReferenceError{"outputs.bogus.version.number", "unknown output"}))
+		})
+
+		It("lists the outputs it refers to", func() {
+			Ω(Output{
+				Params: Params{"a": "((outputs.bump.version.number))", "b": "((outputs.bump.version.other))"},
+				Source: Source{"c": []interface{}{"((outputs.tag.metadata.sha))"}},
+			}.OutputReferences()).Should(ConsistOf("bump", "tag"))
+		})
+	})
+
+	Describe("validating", func() {
+		var build Build
+
+		BeforeEach(func() {
+			build = Build{
+				Config: Config{
+					Image: "some-image",
+					Run:   RunConfig{Path: "some-script"},
+					Inputs: []InputConfig{
+						{Name: "some-input", Path: "some/path"},
+					},
+				},
+				Inputs: []Input{
+					{Name: "some-input", Type: "git"},
+				},
+				Outputs: []Output{
+					{Name: "some-output", Type: "git", On: OutputConditions{OutputConditionSuccess}},
+				},
+			}
+		})
+
+		It("passes a complete build", func() {
+			Ω(build.Validate()).Should(Succeed())
+			Ω(build.ValidateMerged()).Should(Succeed())
+		})
+
+		It("reports every problem at once", func() {
+			build.Config = Config{
+				Inputs: []InputConfig{{Name: "bogus-input"}},
+			}
+			build.Inputs = append(build.Inputs, Input{Name: "some-input"}, Input{Type: "git"})
+			build.Outputs = append(build.Outputs, Output{On: OutputConditions{"sometimes"}})
+
+			Ω(build.Validate()).Should(Equal(ValidationError{
+				Problems: []string{
+					"no image specified",
+					"no run path specified",
+					"input 'some-input' is specified more than once",
+					"input 'some-input' has no type",
+					"input #3 has no name",
+					"config refers to unknown input 'bogus-input'",
+					"output #2 has no name",
+					"output '' has no type",
+					"output '' has unknown condition 'sometimes'",
+				},
+			}))
+		})
+
+		Context("when an input provides a config file", func() {
+			BeforeEach(func() {
+				build.Config = Config{}
+				build.Inputs[0].ConfigPath = "build.yml"
+			})
+
+			It("does not yet require the image and run config", func() {
+				Ω(build.Validate()).Should(Succeed())
+			})
+
+			It("requires them once merged", func() {
+				Ω(build.ValidateMerged()).Should(Equal(ValidationError{
+					Problems: []string{"no image specified", "no run path specified"},
+				}))
+			})
+		})
+
+		It("validates retry policies", func() {
+			build.Inputs[0].Retry = &RetryPolicy{Attempts: -1}
+			build.Outputs[0].Retry = &RetryPolicy{Backoff: Duration(-time.Second)}
+
+			Ω(build.Validate()).Should(Equal(ValidationError{
+				Problems: []string{
+					"input 'some-input' has negative retry attempts",
+					"output 'some-output' has negative retry backoff",
+				},
+			}))
+		})
+
+		It("validates timeouts", func() {
+			build.Inputs[0].Timeout = Duration(-time.Second)
+			build.Outputs[0].Timeout = Duration(-time.Second)
+
+			Ω(build.Validate()).Should(Equal(ValidationError{
+				Problems: []string{
+					"input 'some-input' has negative timeout",
+					"output 'some-output' has negative timeout",
+				},
+			}))
+		})
+
+		It("validates output dependencies", func() {
+			build.Outputs = []Output{
+				{
+					Name:      "bump",
+					Type:      "semver",
+					DependsOn: []string{"tag"},
+				},
+				{
+					Name:      "tag",
+					Type:      "git",
+					DependsOn: []string{"bump", "bogus"},
+					Params:    Params{"tag": "((outputs.bump.version.number))"},
+				},
+				{
+					Name:   "push",
+					Type:   "docker-image",
+					Params: Params{"tags": []interface{}{"((outputs.bump.version.number))"}},
+				},
+				{Name: "push", Type: "docker-image"},
+			}
+
+			Ω(build.Validate()).Should(Equal(ValidationError{
+				Problems: []string{
+					"output 'tag' depends on unknown output 'bogus'",
+					"output 'push' refers to output 'bump' without depending on it",
+					"output 'push' is specified more than once",
+					"outputs have a dependency cycle: bump -> tag -> bump",
+				},
+			}))
+		})
+
+		It("validates a config on its own", func() {
+			Ω(Config{Image: "some-image"}.Validate()).Should(Equal(ValidationError{
+				Problems: []string{"no run path specified"},
+			}))
+		})
+
+		It("describes the problems in its error message", func() {
+			Ω(ValidationError{Problems: []string{"a", "b"}}.Error()).Should(Equal("invalid build: a; b"))
+		})
+	})
+
+	Describe("output conditions", func() {
+		It("are satisfied by builds that ended the way they describe", func() {
+			Ω(OutputConditions{OutputConditionSuccess}.SatisfiedBy(StatusSucceeded, 0)).Should(BeTrue())
+			Ω(OutputConditions{OutputConditionSuccess}.SatisfiedBy(StatusFailed, 1)).Should(BeFalse())
+
+			Ω(OutputConditions{OutputConditionFailure}.SatisfiedBy(StatusFailed, 1)).Should(BeTrue())
+			Ω(OutputConditions{OutputConditionFailure}.SatisfiedBy(StatusAborted, 0)).Should(BeFalse())
+
+			Ω(OutputConditions{OutputConditionAborted}.SatisfiedBy(StatusAborted, 0)).Should(BeTrue())
+			Ω(OutputConditions{OutputConditionAborted}.SatisfiedBy(StatusErrored, 0)).Should(BeFalse())
+
+			Ω(OutputConditions{OutputConditionErrored}.SatisfiedBy(StatusErrored, 0)).Should(BeTrue())
+			Ω(OutputConditions{OutputConditionErrored}.SatisfiedBy(StatusFailed, 1)).Should(BeFalse())
+		})
+
+		It("match exit status ranges of builds whose process exited", func() {
+			conditions := OutputConditions{"exit:2-4", "exit:7"}
+
+			Ω(conditions.SatisfiedBy(StatusFailed, 1)).Should(BeFalse())
+			Ω(conditions.SatisfiedBy(StatusFailed, 2)).Should(BeTrue())
+			Ω(conditions.SatisfiedBy(StatusFailed, 4)).Should(BeTrue())
+			Ω(conditions.SatisfiedBy(StatusFailed, 5)).Should(BeFalse())
+			Ω(conditions.SatisfiedBy(StatusFailed, 7)).Should(BeTrue())
+			Ω(conditions.SatisfiedBy(StatusAborted, 2)).Should(BeFalse())
+
+			Ω(OutputConditions{"exit:0"}.SatisfiedBy(StatusSucceeded, 0)).Should(BeTrue())
+		})
+
+		It("validates them", func() {
+			for _, valid := range []OutputCondition{"success", "failure", "aborted", "errored", "exit:0", "exit:1-255"} {
+				Ω(valid.IsValid()).Should(BeTrue(), string(valid))
+			}
+
+			for _, invalid := range []OutputCondition{"sometimes", "exit:", "exit:-1", "exit:3-2", "exit:a-b", "exit:1-"} {
+				Ω(invalid.IsValid()).Should(BeFalse(), string(invalid))
+			}
+		})
+
+		It("are planned for each way a build can end", func() {
+			plan := Build{
+				Outputs: []Output{
+					{Name: "success", On: OutputConditions{OutputConditionSuccess}},
+					{Name: "some-failures", On: OutputConditions{"exit:2-3"}},
+					{Name: "aborted-or-errored", On: OutputConditions{OutputConditionAborted, OutputConditionErrored}},
+				},
+			}.Plan("/tmp/build/src")
+
+			Ω(plan.Outputs).Should(Equal(OutputsPlan{
+				OnSuccess: []string{"success"},
+				OnFailure: []string{"some-failures"},
+				OnAborted: []string{"aborted-or-errored"},
+				OnErrored: []string{"aborted-or-errored"},
+			}))
+		})
+	})
+
+	Describe("retry policies", func() {
+		It("makes one attempt by default", func() {
+			var policy *RetryPolicy
+			Ω(policy.MaxAttempts()).Should(Equal(1))
+			Ω((&RetryPolicy{}).MaxAttempts()).Should(Equal(1))
+			Ω((&RetryPolicy{Attempts: 3}).MaxAttempts()).Should(Equal(3))
+		})
+
+		It("doubles the backoff for each retry", func() {
+			policy := &RetryPolicy{Attempts: 4, Backoff: Duration(time.Second)}
+			Ω(policy.BackoffBefore(2)).Should(Equal(time.Second))
+			Ω(policy.BackoffBefore(3)).Should(Equal(2 * time.Second))
+			Ω(policy.BackoffBefore(4)).Should(Equal(4 * time.Second))
+		})
+
+		It("stops doubling the backoff at the maximum", func() {
+			policy := &RetryPolicy{Attempts: 100, Backoff: Duration(time.Minute)}
+			Ω(policy.BackoffBefore(4)).Should(Equal(4 * time.Minute))
+			Ω(policy.BackoffBefore(5)).Should(Equal(MaxRetryBackoff))
+			Ω(policy.BackoffBefore(100)).Should(Equal(MaxRetryBackoff))
+		})
+
+		It("does not double a backoff longer than the maximum", func() {
+			policy := &RetryPolicy{Attempts: 100, Backoff: Duration(time.Hour)}
+			Ω(policy.BackoffBefore(2)).Should(Equal(time.Hour))
+			Ω(policy.BackoffBefore(100)).Should(Equal(time.Hour))
+		})
+
+		It("represents the backoff as a duration string", func() {
+			payload, err := json.Marshal(RetryPolicy{Attempts: 2, Backoff: Duration(90 * time.Second)})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(payload).Should(MatchJSON(`{"attempts":2,"backoff":"1m30s"}`))
+
+			var policy RetryPolicy
+			err = json.Unmarshal(payload, &policy)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(policy.Backoff).Should(Equal(Duration(90 * time.Second)))
+
+			err = json.Unmarshal([]byte(`{"backoff":"bogus"}`), &policy)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,25 @@
+package turbine
+
+// why a check failed, as returned by the check endpoints
+type CheckError struct {
+	Code    CheckErrorCode `json:"code"`
+	Message string         `json:"message"`
+
+	// set when the check script itself failed
+	ExitStatus int    `json:"exit_status,omitempty"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+}
+
+type CheckErrorCode string
+
+const (
+	CheckErrorInvalidRequest          CheckErrorCode = "invalid-request"
+	CheckErrorUnknownResourceType     CheckErrorCode = "unknown-resource-type"
+	CheckErrorContainerCreationFailed CheckErrorCode = "container-creation-failed"
+	CheckErrorScriptFailed            CheckErrorCode = "script-failed"
+	CheckErrorScriptTimedOut          CheckErrorCode = "script-timed-out"
+	CheckErrorMalformedOutput         CheckErrorCode = "malformed-output"
+	CheckErrorAborted                 CheckErrorCode = "aborted"
+	CheckErrorInternal                CheckErrorCode = "internal-error"
+)
@@ -2,12 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"expvar"
 	"flag"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"time"
 
+	garden_api "github.com/cloudfoundry-incubator/garden/api"
 	GardenClient "github.com/cloudfoundry-incubator/garden/client"
 	GardenConnection "github.com/cloudfoundry-incubator/garden/client/connection"
 	"github.com/pivotal-golang/lager"
@@ -16,12 +19,16 @@ import (
 	"github.com/tedsuo/ifrit/http_server"
 	"github.com/tedsuo/ifrit/sigmon"
 
+	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/api"
 	"github.com/concourse/turbine/builder"
 	"github.com/concourse/turbine/builder/inputs"
 	"github.com/concourse/turbine/builder/outputs"
 	"github.com/concourse/turbine/config"
 	"github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/runtime"
+	gardenruntime "github.com/concourse/turbine/runtime/garden"
+	"github.com/concourse/turbine/runtime/local"
 	"github.com/concourse/turbine/scheduler"
 	"github.com/concourse/turbine/snapshotter"
 )
@@ -44,6 +51,12 @@ var debugListenAddr = flag.String(
 	"port for the pprof debugger to listen on",
 )
 
+var runtimeName = flag.String(
+	"runtime",
+	"garden",
+	"container runtime to use (garden or local)",
+)
+
 var gardenNetwork = flag.String(
 	"gardenNetwork",
 	"tcp",
@@ -56,6 +69,12 @@ var gardenAddr = flag.String(
 	"garden API connection address",
 )
 
+var localDepot = flag.String(
+	"localDepot",
+	"/tmp/turbine-containers",
+	"directory in which the local runtime keeps its containers",
+)
+
 var resourceTypes = flag.String(
 	"resourceTypes",
 	`{
@@ -70,6 +89,48 @@ var resourceTypes = flag.String(
 	"map of resource type to its docker image",
 )
 
+var resourceTimeouts = flag.String(
+	"resourceTimeouts",
+	`{}`,
+	"map of resource type to how long its scripts may run, e.g. 10m",
+)
+
+var checkCacheTTL = flag.Duration(
+	"checkCacheTTL",
+	10*time.Second,
+	"how long to reuse the result of a check for identical checks",
+)
+
+var eventKeepaliveInterval = flag.Duration(
+	"eventKeepaliveInterval",
+	30*time.Second,
+	"how often to send keepalives on idle build event streams (0 to disable)",
+)
+
+var poolMinIdle = flag.Int(
+	"poolMinIdle",
+	0,
+	"number of idle containers to keep warm for each resource type",
+)
+
+var poolMaxIdle = flag.Int(
+	"poolMaxIdle",
+	0,
+	"maximum number of used containers to keep for reuse by checks of each resource type",
+)
+
+var poolIdleTTL = flag.Duration(
+	"poolIdleTTL",
+	5*time.Minute,
+	"how long a container may sit idle in the pool before being destroyed",
+)
+
+var maxParallelInputs = flag.Int(
+	"maxParallelInputs",
+	8,
+	"maximum number of inputs to fetch at once for a build (0 for no limit)",
+)
+
 var snapshotPath = flag.String(
 	"snapshotPath",
 	"/tmp/builds-snapshot.json",
@@ -79,69 +140,107 @@ var snapshotPath = flag.String(
 func main() {
 	flag.Parse()
 
-	gardenClient := GardenClient.New(GardenConnection.New(
-		*gardenNetwork,
-		*gardenAddr,
-	))
-
 	logger := lager.NewLogger("turbine")
 	logger.RegisterSink(lager.NewWriterSink(os.Stdout, lager.DEBUG))
 
+	var containerRuntime runtime.Runtime
+
+	switch *runtimeName {
+	case "garden":
+		gardenClient := GardenClient.New(GardenConnection.New(
+			*gardenNetwork,
+			*gardenAddr,
+		))
+
+		waitForGarden(logger, gardenClient)
+
+		containerRuntime = gardenruntime.New(gardenClient)
+
+	case "local":
+		containerRuntime = local.New(*localDepot)
+
+	default:
+		logger.Fatal("unknown-runtime", errors.New("unknown runtime: "+*runtimeName))
+	}
+
 	resourceTypesMap := map[string]string{}
 	err := json.Unmarshal([]byte(*resourceTypes), &resourceTypesMap)
 	if err != nil {
 		logger.Fatal("failed-to-parse-resource-types", err)
 	}
 
+	resourceTimeoutsMap := map[string]turbine.Duration{}
+	err = json.Unmarshal([]byte(*resourceTimeouts), &resourceTimeoutsMap)
+	if err != nil {
+		logger.Fatal("failed-to-parse-resource-timeouts", err)
+	}
+
 	var resourceTypesConfig config.ResourceTypes
 	for typ, image := range resourceTypesMap {
 		resourceTypesConfig = append(resourceTypesConfig, config.ResourceType{
-			Name:  typ,
-			Image: image,
+			Name:    typ,
+			Image:   image,
+			Timeout: time.Duration(resourceTimeoutsMap[typ]),
 		})
 	}
 
-	resourceTracker := resource.NewTracker(resourceTypesConfig, gardenClient)
+	pool := resource.NewContainerPool(
+		logger.Session("pool"),
+		resourceTypesConfig,
+		containerRuntime,
+		config.Pool{
+			MinIdle: *poolMinIdle,
+			MaxIdle: *poolMaxIdle,
+			IdleTTL: *poolIdleTTL,
+		},
+		resource.NewClock(),
+	)
+
+	expvar.Publish("resourcePool", expvar.Func(func() interface{} {
+		return pool.Stats()
+	}))
+
+	resourceTracker := resource.NewTracker(resourceTypesConfig, containerRuntime, pool)
+
+	clock := scheduler.NewClock()
 
 	builder := builder.NewBuilder(
-		gardenClient,
-		inputs.NewParallelFetcher(resourceTracker),
-		outputs.NewParallelPerformer(resourceTracker),
+		containerRuntime,
+		inputs.NewParallelFetcher(resourceTracker, *maxParallelInputs, clock),
+		outputs.NewParallelPerformer(resourceTracker, clock),
+		clock,
 	)
 
-	scheduler := scheduler.NewScheduler(logger.Session("scheduler"), builder, scheduler.NewClock())
+	scheduler := scheduler.NewScheduler(logger.Session("scheduler"), builder, clock)
 
 	drain := make(chan struct{})
 
-	handler, err := api.New(logger.Session("api"), scheduler, resourceTracker, "http://"+*peerAddr, drain)
+	handler, err := api.New(
+		logger.Session("api"),
+		scheduler,
+		resourceTracker,
+		resourceTypesConfig,
+		*checkCacheTTL,
+		*eventKeepaliveInterval,
+		clock,
+		"http://"+*peerAddr,
+		drain,
+	)
 	if err != nil {
 		logger.Fatal("failed-to-initialize-handler", err)
 	}
 
-	var pingErr error
-	for i := 0; i < 10; i++ {
-		pingErr = gardenClient.Ping()
-		if pingErr == nil {
-			break
-		}
-
-		time.Sleep(10 * time.Second)
-	}
-
-	if pingErr != nil {
-		logger.Fatal("failed-to-ping-garden", err)
-	}
-
 	parallel := grouper.NewParallel(os.Interrupt, []grouper.Member{
 		{"api", http_server.New(*listenAddr, handler)},
 		{"debug", http_server.New(*debugListenAddr, http.DefaultServeMux)},
 		{"drainer", &drainer{drain}},
+		{"pool", pool},
 	})
 
 	// Snapshotter must start before the rest to avoid race conditions when
 	// re-attaching to builds.
 	script := grouper.NewOrdered(os.Interrupt, []grouper.Member{
-		{"snapshotter", snapshotter.NewSnapshotter(logger.Session("snapshotter"), *snapshotPath, scheduler)},
+		{"snapshotter", snapshotter.NewSnapshotter(logger.Session("snapshotter"), *snapshotPath, scheduler, clock)},
 		{"parallel", parallel},
 	})
 
@@ -160,6 +259,22 @@ func main() {
 	}
 }
 
+func waitForGarden(logger lager.Logger, gardenClient garden_api.Client) {
+	var pingErr error
+	for i := 0; i < 10; i++ {
+		pingErr = gardenClient.Ping()
+		if pingErr == nil {
+			break
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+
+	if pingErr != nil {
+		logger.Fatal("failed-to-ping-garden", pingErr)
+	}
+}
+
 type drainer struct {
 	drain chan<- struct{}
 }
@@ -1,7 +1,25 @@
 package config
 
+import "time"
+
 type Config struct {
 	ResourceTypes ResourceTypes
+	Pool          Pool
+}
+
+// how many idle containers to keep around for each resource type
+type Pool struct {
+	// fresh containers created ahead of time so that they are ready when
+	// needed
+	MinIdle int
+
+	// used containers kept for reuse by checks, beyond which released
+	// containers are destroyed
+	MaxIdle int
+
+	// after which an idle container is destroyed and, if needed to stay
+	// above MinIdle, replaced; zero means idle containers never expire
+	IdleTTL time.Duration
 }
 
 type ResourceTypes []ResourceType
@@ -9,6 +27,10 @@ type ResourceTypes []ResourceType
 type ResourceType struct {
 	Name  string
 	Image string
+
+	// how long its scripts may run unless a request says otherwise; zero
+	// means no limit
+	Timeout time.Duration
 }
 
 func (types ResourceTypes) Lookup(name string) (ResourceType, bool) {
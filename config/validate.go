@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/concourse/turbine"
+)
+
+// Problems lists the build's inputs and outputs whose types are not
+// configured. Missing types are left to turbine.Build's validation.
+func (types ResourceTypes) Problems(build turbine.Build) []string {
+	problems := []string{}
+
+	for _, input := range build.Inputs {
+		if !types.known(input.Type) {
+			problems = append(problems, fmt.Sprintf("input '%s' has unknown type '%s'", input.Name, input.Type))
+		}
+	}
+
+	for _, output := range build.Outputs {
+		if !types.known(output.Type) {
+			problems = append(problems, fmt.Sprintf("output '%s' has unknown type '%s'", output.Name, output.Type))
+		}
+	}
+
+	return problems
+}
+
+func (types ResourceTypes) known(typ string) bool {
+	if typ == "" {
+		return true
+	}
+
+	_, found := types.Lookup(typ)
+	return found
+}
@@ -0,0 +1,9 @@
+package event
+
+import "time"
+
+// Clock tells the Hub when events are emitted, and Writers when to flush
+type Clock interface {
+	CurrentTime() time.Time
+	After(time.Duration) <-chan time.Time
+}
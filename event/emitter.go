@@ -1,6 +1,6 @@
 package event
 
-const CURRENT_VERSION Version = "1.1"
+const CURRENT_VERSION Version = "1.2"
 
 type Emitter interface {
 	EmitEvent(Event)
@@ -3,6 +3,7 @@ package event
 type Error struct {
 	Message string `json:"message"`
 	Origin  Origin `json:"origin,omitempty"`
+	Timestamp
 }
 
 func (Error) EventType() EventType { return EventTypeError }
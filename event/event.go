@@ -38,4 +38,7 @@ const (
 
 	// output completed
 	EventTypeOutput EventType = "output"
+
+	// progress reported by a resource script
+	EventTypeProgress EventType = "progress"
 )
@@ -0,0 +1,106 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter selects events by type and origin. An empty Filter matches every
+// event. Version and end events are always matched, as clients need them to
+// read the stream.
+type Filter struct {
+	Types   []EventType
+	Origins []Origin
+}
+
+// ParseOrigin parses an origin of the form <type> or <type>:<name>, e.g.
+// "run" or "input:repo". If the name is omitted, any origin of the type
+// matches.
+func ParseOrigin(str string) (Origin, error) {
+	segments := strings.SplitN(str, ":", 2)
+
+	origin := Origin{Type: OriginType(segments[0])}
+	if len(segments) == 2 {
+		origin.Name = segments[1]
+	}
+
+	switch origin.Type {
+	case OriginTypeInput, OriginTypeOutput, OriginTypeRun:
+		return origin, nil
+	default:
+		return Origin{}, fmt.Errorf("unknown origin type: %q", origin.Type)
+	}
+}
+
+// ParseEventType parses an event type, e.g. "log".
+func ParseEventType(str string) (EventType, error) {
+	typ := EventType(str)
+
+	switch typ {
+	case EventTypeVersion, EventTypeEnd, EventTypeLog, EventTypeStatus,
+		EventTypeInitialize, EventTypeStart, EventTypeFinish, EventTypeError,
+		EventTypeInput, EventTypeOutput, EventTypeProgress:
+		return typ, nil
+	default:
+		return EventTypeInvalid, fmt.Errorf("unknown event type: %q", str)
+	}
+}
+
+func (filter Filter) Matches(ev Event) bool {
+	switch ev.EventType() {
+	case EventTypeVersion, EventTypeEnd:
+		return true
+	}
+
+	if len(filter.Types) > 0 && !filter.matchesType(ev.EventType()) {
+		return false
+	}
+
+	if len(filter.Origins) > 0 {
+		origin, found := originOf(ev)
+		if !found || !filter.matchesOrigin(origin) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (filter Filter) matchesType(t EventType) bool {
+	for _, allowed := range filter.Types {
+		if t == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (filter Filter) matchesOrigin(origin Origin) bool {
+	for _, allowed := range filter.Origins {
+		if origin.Type == allowed.Type && (allowed.Name == "" || origin.Name == allowed.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// originOf returns where the event came from, if it is specific to an input,
+// an output, or the build's execution
+func originOf(ev Event) (Origin, bool) {
+	switch e := ev.(type) {
+	case Log:
+		return e.Origin, true
+	case Error:
+		return e.Origin, e.Origin.Type != OriginTypeInvalid
+	case Progress:
+		return e.Origin, true
+	case Input:
+		return Origin{Type: OriginTypeInput, Name: e.Input.Name}, true
+	case Output:
+		return Origin{Type: OriginTypeOutput, Name: e.Output.Name}, true
+	default:
+		return Origin{}, false
+	}
+}
@@ -0,0 +1,93 @@
+package event_test
+
+import (
+	"github.com/concourse/turbine"
+	. "github.com/concourse/turbine/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filter", func() {
+	runLog := Log{Origin: Origin{Type: OriginTypeRun, Name: "stdout"}, Payload: "hi"}
+	repoLog := Log{Origin: Origin{Type: OriginTypeInput, Name: "repo"}, Payload: "cloning"}
+	status := Status{Status: turbine.StatusStarted}
+
+	It("matches everything when empty", func() {
+		Ω(Filter{}.Matches(runLog)).Should(BeTrue())
+		Ω(Filter{}.Matches(status)).Should(BeTrue())
+	})
+
+	It("matches events of the given types", func() {
+		filter := Filter{Types: []EventType{EventTypeStatus}}
+
+		Ω(filter.Matches(status)).Should(BeTrue())
+		Ω(filter.Matches(runLog)).Should(BeFalse())
+	})
+
+	It("matches events from the given origins", func() {
+		filter := Filter{Origins: []Origin{{Type: OriginTypeInput, Name: "repo"}}}
+
+		Ω(filter.Matches(repoLog)).Should(BeTrue())
+		Ω(filter.Matches(Input{Input: turbine.Input{Name: "repo"}})).Should(BeTrue())
+		Ω(filter.Matches(Error{Message: "oh no", Origin: Origin{Type: OriginTypeInput, Name: "repo"}})).Should(BeTrue())
+
+		Ω(filter.Matches(Input{Input: turbine.Input{Name: "other"}})).Should(BeFalse())
+		Ω(filter.Matches(runLog)).Should(BeFalse())
+		Ω(filter.Matches(status)).Should(BeFalse())
+		Ω(filter.Matches(Error{Message: "oh no"})).Should(BeFalse())
+	})
+
+	It("matches any origin of a type if no name is given", func() {
+		filter := Filter{Origins: []Origin{{Type: OriginTypeOutput}}}
+
+		Ω(filter.Matches(Output{Output: turbine.Output{Name: "some-output"}})).Should(BeTrue())
+		Ω(filter.Matches(Progress{Origin: Origin{Type: OriginTypeOutput, Name: "other-output"}})).Should(BeTrue())
+		Ω(filter.Matches(repoLog)).Should(BeFalse())
+	})
+
+	It("requires both the type and origin to match", func() {
+		filter := Filter{
+			Types:   []EventType{EventTypeLog},
+			Origins: []Origin{{Type: OriginTypeRun}},
+		}
+
+		Ω(filter.Matches(runLog)).Should(BeTrue())
+		Ω(filter.Matches(repoLog)).Should(BeFalse())
+		Ω(filter.Matches(Error{Origin: Origin{Type: OriginTypeRun}})).Should(BeFalse())
+	})
+
+	It("always matches version and end events", func() {
+		filter := Filter{Types: []EventType{EventTypeStatus}, Origins: []Origin{{Type: OriginTypeRun}}}
+
+		Ω(filter.Matches(CURRENT_VERSION)).Should(BeTrue())
+		Ω(filter.Matches(End{})).Should(BeTrue())
+	})
+
+	Describe("parsing origins", func() {
+		It("parses a type with an optional name", func() {
+			Ω(ParseOrigin("run")).Should(Equal(Origin{Type: OriginTypeRun}))
+			Ω(ParseOrigin("input:repo")).Should(Equal(Origin{Type: OriginTypeInput, Name: "repo"}))
+		})
+
+		It("fails on unknown types", func() {
+			_, err := ParseOrigin("bogus:repo")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("parsing event types", func() {
+		It("parses known types", func() {
+			Ω(ParseEventType("log")).Should(Equal(EventTypeLog))
+			Ω(ParseEventType("progress")).Should(Equal(EventTypeProgress))
+		})
+
+		It("fails on unknown types", func() {
+			_, err := ParseEventType("bogus")
+			Ω(err).Should(HaveOccurred())
+
+			_, err = ParseEventType("")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
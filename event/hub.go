@@ -3,6 +3,8 @@ package event
 import "sync"
 
 type Hub struct {
+	clock Clock
+
 	events []*eventOccurrence
 
 	closed bool
@@ -15,8 +17,10 @@ type eventOccurrence struct {
 	occurred chan struct{}
 }
 
-func NewHub() *Hub {
+func NewHub(clock Clock) *Hub {
 	return &Hub{
+		clock: clock,
+
 		lock: new(sync.RWMutex),
 		events: []*eventOccurrence{
 			&eventOccurrence{
@@ -26,7 +30,15 @@ func NewHub() *Hub {
 	}
 }
 
+// EmitEvent records the event, timestamping it with the current time if it
+// does not already have one.
 func (h *Hub) EmitEvent(event Event) {
+	h.Replay(timestamped(event, h.clock.CurrentTime().Unix()))
+}
+
+// Replay records a previously emitted event as-is, e.g. when restoring a
+// build's events from a snapshot.
+func (h *Hub) Replay(event Event) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
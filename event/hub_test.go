@@ -2,8 +2,10 @@ package event_test
 
 import (
 	"sync"
+	"time"
 
 	. "github.com/concourse/turbine/event"
+	efakes "github.com/concourse/turbine/event/fakes"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -11,14 +13,18 @@ import (
 
 var _ = Describe("Hub", func() {
 	var (
-		hub *Hub
+		clock *efakes.FakeClock
+		hub   *Hub
 
 		stopSubscribers chan struct{}
 		subscribers     *sync.WaitGroup
 	)
 
 	BeforeEach(func() {
-		hub = NewHub()
+		clock = new(efakes.FakeClock)
+		clock.CurrentTimeReturns(time.Unix(42, 0))
+
+		hub = NewHub(clock)
 
 		stopSubscribers = make(chan struct{})
 		subscribers = new(sync.WaitGroup)
@@ -39,7 +45,7 @@ var _ = Describe("Hub", func() {
 
 	Context("when an event is emitted", func() {
 		JustBeforeEach(func() {
-			hub.EmitEvent(Start{Time: 123})
+			hub.EmitEvent(Start{Timestamp: Timestamp{Time: 123}})
 		})
 
 		Describe("a late subscriber", func() {
@@ -48,7 +54,7 @@ var _ = Describe("Hub", func() {
 
 				subscribe(0, events)
 
-				Eventually(events).Should(Receive(Equal(Start{Time: 123})))
+				Eventually(events).Should(Receive(Equal(Start{Timestamp: Timestamp{Time: 123}})))
 			})
 		})
 
@@ -66,16 +72,54 @@ var _ = Describe("Hub", func() {
 			})
 
 			It("emits the event to the active subscriber", func() {
-				Eventually(receivedEvents).Should(Receive(Equal(Start{Time: 123})))
+				Eventually(receivedEvents).Should(Receive(Equal(Start{Timestamp: Timestamp{Time: 123}})))
 			})
 		})
 	})
 
+	Describe("timestamping", func() {
+		It("stamps events with the current time", func() {
+			hub.EmitEvent(Log{Payload: "some-log"})
+			hub.EmitEvent(Input{})
+
+			Ω(hub.Events()).Should(Equal([]Event{
+				Log{Payload: "some-log", Timestamp: Timestamp{Time: 42}},
+				Input{Timestamp: Timestamp{Time: 42}},
+			}))
+		})
+
+		It("keeps times that events already have", func() {
+			hub.EmitEvent(Log{Payload: "some-log", Timestamp: Timestamp{Time: 1}})
+
+			Ω(hub.Events()).Should(Equal([]Event{
+				Log{Payload: "some-log", Timestamp: Timestamp{Time: 1}},
+			}))
+		})
+
+		It("does not stamp stream markers", func() {
+			hub.EmitEvent(Version("1.0"))
+			hub.EmitEvent(End{})
+
+			Ω(hub.Events()).Should(Equal([]Event{
+				Version("1.0"),
+				End{},
+			}))
+		})
+
+		It("does not stamp replayed events", func() {
+			hub.Replay(Log{Payload: "some-log"})
+
+			Ω(hub.Events()).Should(Equal([]Event{
+				Log{Payload: "some-log"},
+			}))
+		})
+	})
+
 	Describe("subscribing", func() {
 		Context("starting from a previous index", func() {
 			BeforeEach(func() {
 				for i := 0; i < 10; i++ {
-					hub.EmitEvent(Start{Time: int64(i)})
+					hub.EmitEvent(Start{Timestamp: Timestamp{Time: int64(i)}})
 				}
 			})
 
@@ -84,11 +128,11 @@ var _ = Describe("Hub", func() {
 
 				subscribe(5, events)
 
-				Ω(<-events).Should(Equal(Start{Time: 5}))
-				Ω(<-events).Should(Equal(Start{Time: 6}))
-				Ω(<-events).Should(Equal(Start{Time: 7}))
-				Ω(<-events).Should(Equal(Start{Time: 8}))
-				Ω(<-events).Should(Equal(Start{Time: 9}))
+				Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 5}}))
+				Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 6}}))
+				Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 7}}))
+				Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 8}}))
+				Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 9}}))
 			})
 
 			Context("when another event is emitted", func() {
@@ -97,15 +141,15 @@ var _ = Describe("Hub", func() {
 
 					subscribe(5, events)
 
-					Ω(<-events).Should(Equal(Start{Time: 5}))
-					Ω(<-events).Should(Equal(Start{Time: 6}))
-					Ω(<-events).Should(Equal(Start{Time: 7}))
-					Ω(<-events).Should(Equal(Start{Time: 8}))
-					Ω(<-events).Should(Equal(Start{Time: 9}))
+					Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 5}}))
+					Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 6}}))
+					Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 7}}))
+					Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 8}}))
+					Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 9}}))
 					Consistently(events).ShouldNot(Receive())
 
-					hub.EmitEvent(Start{Time: 10})
-					Ω(<-events).Should(Equal(Start{Time: 10}))
+					hub.EmitEvent(Start{Timestamp: Timestamp{Time: 10}})
+					Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 10}}))
 				})
 			})
 		})
@@ -128,9 +172,9 @@ var _ = Describe("Hub", func() {
 
 		Context("after events are emitted", func() {
 			BeforeEach(func() {
-				hub.EmitEvent(Start{Time: 1})
-				hub.EmitEvent(Start{Time: 2})
-				hub.EmitEvent(Start{Time: 3})
+				hub.EmitEvent(Start{Timestamp: Timestamp{Time: 1}})
+				hub.EmitEvent(Start{Timestamp: Timestamp{Time: 2}})
+				hub.EmitEvent(Start{Timestamp: Timestamp{Time: 3}})
 			})
 
 			It("dispatches the previous events before closing the subscription", func() {
@@ -138,7 +182,7 @@ var _ = Describe("Hub", func() {
 
 				subscribe(2, events)
 
-				Ω(<-events).Should(Equal(Start{Time: 3}))
+				Ω(<-events).Should(Equal(Start{Timestamp: Timestamp{Time: 3}}))
 
 				_, ok := <-events
 				Ω(ok).Should(BeFalse())
@@ -158,9 +202,9 @@ var _ = Describe("Hub", func() {
 				})
 
 				It("dispatches the events before closing the subscribers", func() {
-					Ω(<-receivedEvents).Should(Equal(Start{Time: 1}))
-					Ω(<-receivedEvents).Should(Equal(Start{Time: 2}))
-					Ω(<-receivedEvents).Should(Equal(Start{Time: 3}))
+					Ω(<-receivedEvents).Should(Equal(Start{Timestamp: Timestamp{Time: 1}}))
+					Ω(<-receivedEvents).Should(Equal(Start{Timestamp: Timestamp{Time: 2}}))
+					Ω(<-receivedEvents).Should(Equal(Start{Timestamp: Timestamp{Time: 3}}))
 					Eventually(receivedEvents).Should(BeClosed())
 				})
 			})
@@ -174,7 +218,7 @@ var _ = Describe("Hub", func() {
 
 		Describe("and then emitting events", func() {
 			It("does not blow up", func() {
-				hub.EmitEvent(Start{Time: 123})
+				hub.EmitEvent(Start{Timestamp: Timestamp{Time: 123}})
 			})
 		})
 	})
@@ -182,15 +226,15 @@ var _ = Describe("Hub", func() {
 	Describe("getting all events", func() {
 		It("returns all events emitted to the hub", func() {
 			hub.EmitEvent(Version("1.0"))
-			hub.EmitEvent(Start{Time: 1})
-			hub.EmitEvent(Start{Time: 2})
-			hub.EmitEvent(Start{Time: 3})
+			hub.EmitEvent(Start{Timestamp: Timestamp{Time: 1}})
+			hub.EmitEvent(Start{Timestamp: Timestamp{Time: 2}})
+			hub.EmitEvent(Start{Timestamp: Timestamp{Time: 3}})
 
 			Ω(hub.Events()).Should(Equal([]Event{
 				Version("1.0"),
-				Start{Time: 1},
-				Start{Time: 2},
-				Start{Time: 3},
+				Start{Timestamp: Timestamp{Time: 1}},
+				Start{Timestamp: Timestamp{Time: 2}},
+				Start{Timestamp: Timestamp{Time: 3}},
 			}))
 		})
 	})
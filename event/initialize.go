@@ -4,6 +4,7 @@ import "github.com/concourse/turbine"
 
 type Initialize struct {
 	BuildConfig turbine.Config `json:"config"`
+	Timestamp
 }
 
 func (Initialize) EventType() EventType { return EventTypeInitialize }
@@ -4,6 +4,7 @@ import "github.com/concourse/turbine"
 
 type Input struct {
 	Input turbine.Input `json:"input"`
+	Timestamp
 }
 
 func (Input) EventType() EventType { return EventTypeInput }
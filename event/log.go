@@ -3,6 +3,8 @@ package event
 type Log struct {
 	Origin  Origin `json:"origin"`
 	Payload string `json:"payload"`
+
+	Timestamp
 }
 
 func (Log) EventType() EventType { return EventTypeLog }
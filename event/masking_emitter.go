@@ -0,0 +1,102 @@
+package event
+
+import "strings"
+
+// replaces secrets in log output and error messages
+const Redacted = "((redacted))"
+
+type maskingEmitter struct {
+	Emitter
+
+	replacer *strings.Replacer
+}
+
+// NewMaskingEmitter wraps the emitter so that the given secrets never appear
+// in emitted Log, Error, or Progress events. Secrets are matched in order, so longer
+// secrets sharing a prefix with shorter ones should come first.
+func NewMaskingEmitter(emitter Emitter, secrets []string) Emitter {
+	replacements := []string{}
+	for _, secret := range secrets {
+		if secret != "" {
+			replacements = append(replacements, secret, Redacted)
+		}
+	}
+
+	if len(replacements) == 0 {
+		return emitter
+	}
+
+	return maskingEmitter{
+		Emitter:  emitter,
+		replacer: strings.NewReplacer(replacements...),
+	}
+}
+
+func (emitter maskingEmitter) EmitEvent(e Event) {
+	emitter.Emitter.EmitEvent(maskEvent(e, emitter.replacer.Replace))
+}
+
+type digestMaskingEmitter struct {
+	Emitter
+
+	digests []SecretDigest
+}
+
+// NewDigestMaskingEmitter masks secrets known only by their digests, e.g.
+// those of builds restored from a snapshot. Unlike with NewMaskingEmitter,
+// secrets split across events can't be recognized, and so aren't masked.
+func NewDigestMaskingEmitter(emitter Emitter, digests []SecretDigest) Emitter {
+	if len(digests) == 0 {
+		return emitter
+	}
+
+	return digestMaskingEmitter{
+		Emitter: emitter,
+		digests: digests,
+	}
+}
+
+func (emitter digestMaskingEmitter) EmitEvent(e Event) {
+	emitter.Emitter.EmitEvent(maskEvent(e, emitter.mask))
+}
+
+func (emitter digestMaskingEmitter) mask(str string) string {
+	masked := []byte{}
+
+	for i := 0; i < len(str); {
+		matched := false
+
+		// digests are longest first
+		for _, digest := range emitter.digests {
+			if i+digest.Length <= len(str) && digest.matches(str[i:i+digest.Length]) {
+				masked = append(masked, Redacted...)
+				i += digest.Length
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			masked = append(masked, str[i])
+			i++
+		}
+	}
+
+	return string(masked)
+}
+
+func maskEvent(e Event, mask func(string) string) Event {
+	switch masked := e.(type) {
+	case Log:
+		masked.Payload = mask(masked.Payload)
+		return masked
+	case Error:
+		masked.Message = mask(masked.Message)
+		return masked
+	case Progress:
+		masked.Message = mask(masked.Message)
+		return masked
+	}
+
+	return e
+}
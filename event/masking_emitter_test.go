@@ -0,0 +1,150 @@
+package event_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/concourse/turbine/event"
+	efakes "github.com/concourse/turbine/event/fakes"
+)
+
+var _ = Describe("MaskingEmitter", func() {
+	var (
+		emitter *efakes.FakeEmitter
+		origin  Origin
+
+		secrets []string
+
+		masking Emitter
+	)
+
+	BeforeEach(func() {
+		emitter = new(efakes.FakeEmitter)
+
+		origin = Origin{
+			Type: OriginTypeRun,
+			Name: "stdout",
+		}
+
+		secrets = []string{"super-secret", "secret"}
+	})
+
+	JustBeforeEach(func() {
+		masking = NewMaskingEmitter(emitter, secrets)
+	})
+
+	It("masks secrets in log payloads", func() {
+		masking.EmitEvent(Log{
+			Payload: "a super-secret and a secret",
+			Origin:  origin,
+		})
+
+		Ω(emitter.EmitEventCallCount()).Should(Equal(1))
+		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Log{
+			Payload: "a ((redacted)) and a ((redacted))",
+			Origin:  origin,
+		}))
+	})
+
+	It("masks secrets in error messages", func() {
+		masking.EmitEvent(Error{
+			Message: "failed with secret",
+			Origin:  origin,
+		})
+
+		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Error{
+			Message: "failed with ((redacted))",
+			Origin:  origin,
+		}))
+	})
+
+	It("masks secrets in progress messages", func() {
+		masking.EmitEvent(Progress{
+			Message: "downloading secret",
+			Current: 1,
+			Origin:  origin,
+		})
+
+		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Progress{
+			Message: "downloading ((redacted))",
+			Current: 1,
+			Origin:  origin,
+		}))
+	})
+
+	It("passes other events through untouched", func() {
+		masking.EmitEvent(Start{Timestamp: Timestamp{Time: 1}})
+
+		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Start{Timestamp: Timestamp{Time: 1}}))
+	})
+
+	It("closes the underlying emitter", func() {
+		masking.Close()
+
+		Ω(emitter.CloseCallCount()).Should(Equal(1))
+	})
+
+	Context("when there are no secrets", func() {
+		BeforeEach(func() {
+			secrets = []string{""}
+		})
+
+		It("returns the emitter as-is", func() {
+			Ω(masking).Should(Equal(emitter))
+		})
+	})
+
+	Context("with digests of the secrets", func() {
+		JustBeforeEach(func() {
+			digests, err := DigestSecrets(secrets)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			masking = NewDigestMaskingEmitter(emitter, digests)
+		})
+
+		It("masks them in log payloads, longest first", func() {
+			masking.EmitEvent(Log{
+				Payload: "a super-secret and a secret",
+				Origin:  origin,
+			})
+
+			Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Log{
+				Payload: "a ((redacted)) and a ((redacted))",
+				Origin:  origin,
+			}))
+		})
+
+		It("masks them in error and progress messages", func() {
+			masking.EmitEvent(Error{Message: "failed with secret"})
+			masking.EmitEvent(Progress{Message: "downloading secret"})
+
+			Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Error{Message: "failed with ((redacted))"}))
+			Ω(emitter.EmitEventArgsForCall(1)).Should(Equal(Progress{Message: "downloading ((redacted))"}))
+		})
+
+		It("passes other events through untouched", func() {
+			masking.EmitEvent(Start{Timestamp: Timestamp{Time: 1}})
+
+			Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Start{Timestamp: Timestamp{Time: 1}}))
+		})
+
+		It("does not keep the secrets", func() {
+			digests, err := DigestSecrets(secrets)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fmt.Sprintf("%#v", digests)).ShouldNot(ContainSubstring("secret"))
+		})
+
+		Context("when there are none", func() {
+			BeforeEach(func() {
+				secrets = []string{""}
+			})
+
+			It("returns the emitter as-is", func() {
+				Ω(masking).Should(Equal(emitter))
+			})
+		})
+	})
+})
@@ -6,10 +6,14 @@ import (
 )
 
 type Message struct {
+	// position of the event in the build's stream, if streamed
+	ID string `json:"-"`
+
 	Event Event `json:"-"`
 }
 
 type eventEnvelope struct {
+	ID           string           `json:"id,omitempty"`
 	Type         EventType        `json:"type"`
 	EventPayload *json.RawMessage `json:"event"`
 }
@@ -22,6 +26,7 @@ func (m Message) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
+	envelope.ID = m.ID
 	envelope.Type = m.Event.EventType()
 	envelope.EventPayload = (*json.RawMessage)(&payload)
 
@@ -41,6 +46,7 @@ func (m *Message) UnmarshalJSON(bytes []byte) error {
 		return err
 	}
 
+	m.ID = envelope.ID
 	m.Event = event
 
 	return nil
@@ -83,6 +89,10 @@ func ParseEvent(t EventType, payload []byte) (Event, error) {
 		event := Output{}
 		err = json.Unmarshal(payload, &event)
 		ev = event
+	case EventTypeProgress:
+		event := Progress{}
+		err = json.Unmarshal(payload, &event)
+		ev = event
 	case EventTypeVersion:
 		event := Version("")
 		err = json.Unmarshal(payload, &event)
@@ -16,7 +16,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 
 	itEncodesAndDecodesToItself := func() {
 		It("encodes and decodes to itself", func() {
-			payload, err := json.Marshal(Message{event})
+			payload, err := json.Marshal(Message{Event: event})
 			Ω(err).ShouldNot(HaveOccurred())
 
 			var decodedMsg Message
@@ -27,6 +27,21 @@ var _ = Describe("Encoding & Decoding messages", func() {
 		})
 	}
 
+	Describe("with an id", func() {
+		It("encodes and decodes it along with the event", func() {
+			payload, err := json.Marshal(Message{ID: "42", Event: Start{Timestamp: Timestamp{Time: 1}}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(payload).Should(MatchJSON(`{"id":"42","type":"start","event":{"time":1}}`))
+
+			var decodedMsg Message
+			err = json.Unmarshal(payload, &decodedMsg)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(decodedMsg).Should(Equal(Message{ID: "42", Event: Start{Timestamp: Timestamp{Time: 1}}}))
+		})
+	})
+
 	Describe("Log", func() {
 		BeforeEach(func() {
 			event = Log{
@@ -35,6 +50,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 					Type: OriginTypeInput,
 					Name: "some-input",
 				},
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
@@ -44,8 +60,8 @@ var _ = Describe("Encoding & Decoding messages", func() {
 	Describe("Status", func() {
 		BeforeEach(func() {
 			event = Status{
-				Status: turbine.StatusSucceeded,
-				Time:   time.Now().Unix(),
+				Status:    turbine.StatusSucceeded,
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
@@ -58,6 +74,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 				BuildConfig: turbine.Config{
 					Image: "some-image",
 				},
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
@@ -67,7 +84,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 	Describe("Start", func() {
 		BeforeEach(func() {
 			event = Start{
-				Time: time.Now().Unix(),
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
@@ -77,7 +94,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 	Describe("Finish", func() {
 		BeforeEach(func() {
 			event = Finish{
-				Time:       time.Now().Unix(),
+				Timestamp:  Timestamp{Time: time.Now().Unix()},
 				ExitStatus: 42,
 			}
 		})
@@ -93,6 +110,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 					Type: OriginTypeInput,
 					Name: "some-input",
 				},
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
@@ -105,6 +123,7 @@ var _ = Describe("Encoding & Decoding messages", func() {
 				Input: turbine.Input{
 					Name: "some-resource",
 				},
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
@@ -117,12 +136,50 @@ var _ = Describe("Encoding & Decoding messages", func() {
 				Output: turbine.Output{
 					Name: "some-resource",
 				},
+				Timestamp: Timestamp{Time: time.Now().Unix()},
 			}
 		})
 
 		itEncodesAndDecodesToItself()
 	})
 
+	Describe("Progress", func() {
+		BeforeEach(func() {
+			event = Progress{
+				Message: "downloading",
+				Current: 50,
+				Total:   100,
+				Unit:    "bytes",
+				Origin: Origin{
+					Type: OriginTypeInput,
+					Name: "some-input",
+				},
+				Timestamp: Timestamp{Time: time.Now().Unix()},
+			}
+		})
+
+		itEncodesAndDecodesToItself()
+	})
+
+	Describe("parsing events from older streams", func() {
+		It("leaves their times zero", func() {
+			event, err := ParseEvent(EventTypeLog, []byte(`{"origin":{"type":"run","name":"stdout"},"payload":"hello"}`))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(event).Should(Equal(Log{
+				Origin:  Origin{Type: OriginTypeRun, Name: "stdout"},
+				Payload: "hello",
+			}))
+
+			event, err = ParseEvent(EventTypeInput, []byte(`{"input":{"name":"some-input"}}`))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(event).Should(Equal(Input{
+				Input: turbine.Input{Name: "some-input"},
+			}))
+		})
+	})
+
 	Describe("Version", func() {
 		BeforeEach(func() {
 			event = Version("1.0")
@@ -1,6 +1,6 @@
 package event
 
-// not an event; used in Log and Error
+// not an event; used in Log, Error, and Progress
 type Origin struct {
 	Type OriginType `json:"type"`
 	Name string     `json:"name"`
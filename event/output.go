@@ -4,6 +4,7 @@ import "github.com/concourse/turbine"
 
 type Output struct {
 	Output turbine.Output `json:"output"`
+	Timestamp
 }
 
 func (Output) EventType() EventType { return EventTypeOutput }
@@ -0,0 +1,18 @@
+package event
+
+// progress reported by a resource script, e.g. bytes downloaded
+type Progress struct {
+	Origin Origin `json:"origin"`
+
+	// e.g. "downloading image"
+	Message string `json:"message,omitempty"`
+
+	// how far along, out of Total, in Unit
+	Current int64  `json:"current"`
+	Total   int64  `json:"total,omitempty"`
+	Unit    string `json:"unit,omitempty"`
+
+	Timestamp
+}
+
+func (Progress) EventType() EventType { return EventTypeProgress }
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+type progressWriter struct {
+	emitter Emitter
+	origin  Origin
+
+	dangling []byte
+}
+
+// NewProgressWriter returns a writer that parses newline-delimited JSON
+// progress records and emits them as Progress events from the given origin.
+// Malformed records are dropped, as progress is only advisory.
+func NewProgressWriter(emitter Emitter, origin Origin) io.Writer {
+	return &progressWriter{
+		emitter: emitter,
+		origin:  origin,
+	}
+}
+
+func (writer *progressWriter) Write(data []byte) (int, error) {
+	text := append(writer.dangling, data...)
+
+	for {
+		newline := bytes.IndexByte(text, '\n')
+		if newline == -1 {
+			break
+		}
+
+		line := text[:newline]
+		text = text[newline+1:]
+
+		var progress Progress
+		err := json.Unmarshal(line, &progress)
+		if err != nil {
+			continue
+		}
+
+		progress.Origin = writer.origin
+
+		writer.emitter.EmitEvent(progress)
+	}
+
+	writer.dangling = append([]byte{}, text...)
+
+	return len(data), nil
+}
@@ -0,0 +1,66 @@
+package event_test
+
+import (
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/concourse/turbine/event"
+	efakes "github.com/concourse/turbine/event/fakes"
+)
+
+var _ = Describe("ProgressWriter", func() {
+	var (
+		emitter *efakes.FakeEmitter
+		origin  Origin
+
+		writer io.Writer
+	)
+
+	BeforeEach(func() {
+		emitter = new(efakes.FakeEmitter)
+
+		origin = Origin{
+			Type: OriginTypeInput,
+			Name: "some-input",
+		}
+
+		writer = NewProgressWriter(emitter, origin)
+	})
+
+	It("emits a progress event for each record", func() {
+		writer.Write([]byte(`{"message":"downloading","current":1,"total":2,"unit":"bytes"}` + "\n" + `{"current":2}` + "\n"))
+
+		Ω(emitter.EmitEventCallCount()).Should(Equal(2))
+		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Progress{
+			Message: "downloading",
+			Current: 1,
+			Total:   2,
+			Unit:    "bytes",
+			Origin:  origin,
+		}))
+		Ω(emitter.EmitEventArgsForCall(1)).Should(Equal(Progress{
+			Current: 2,
+			Origin:  origin,
+		}))
+	})
+
+	It("waits for records that are split across writes to finish", func() {
+		writer.Write([]byte(`{"curr`))
+		Ω(emitter.EmitEventCallCount()).Should(Equal(0))
+
+		writer.Write([]byte(`ent":1}` + "\n"))
+		Ω(emitter.EmitEventCallCount()).Should(Equal(1))
+		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Progress{
+			Current: 1,
+			Origin:  origin,
+		}))
+	})
+
+	It("drops malformed records", func() {
+		writer.Write([]byte("ß\n" + `{"current":1}` + "\n"))
+
+		Ω(emitter.EmitEventCallCount()).Should(Equal(1))
+	})
+})
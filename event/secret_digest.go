@@ -0,0 +1,59 @@
+package event
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"sort"
+)
+
+// SecretDigest identifies a secret by a salted hash of it, so that output
+// can be masked without keeping the secret itself, e.g. in snapshots.
+type SecretDigest struct {
+	Length int    `json:"length"`
+	Salt   []byte `json:"salt"`
+	Sum    []byte `json:"sum"`
+}
+
+// DigestSecrets digests each secret with a salt of its own, longest first.
+func DigestSecrets(secrets []string) ([]SecretDigest, error) {
+	digests := []SecretDigest{}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		salt := make([]byte, 16)
+		_, err := rand.Read(salt)
+		if err != nil {
+			return nil, err
+		}
+
+		digests = append(digests, SecretDigest{
+			Length: len(secret),
+			Salt:   salt,
+			Sum:    saltedSum(salt, secret),
+		})
+	}
+
+	sort.Sort(byDigestLength(digests))
+
+	return digests, nil
+}
+
+func (digest SecretDigest) matches(str string) bool {
+	return len(str) == digest.Length && bytes.Equal(saltedSum(digest.Salt, str), digest.Sum)
+}
+
+func saltedSum(salt []byte, str string) []byte {
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write([]byte(str))
+	return hash.Sum(nil)
+}
+
+type byDigestLength []SecretDigest
+
+func (s byDigestLength) Len() int           { return len(s) }
+func (s byDigestLength) Less(i, j int) bool { return s[i].Length > s[j].Length }
+func (s byDigestLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -1,7 +1,7 @@
 package event
 
 type Start struct {
-	Time int64 `json:"time"`
+	Timestamp
 }
 
 func (Start) EventType() EventType { return EventTypeStart }
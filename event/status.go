@@ -4,7 +4,7 @@ import "github.com/concourse/turbine"
 
 type Status struct {
 	Status turbine.Status `json:"status"`
-	Time   int64          `json:"time"`
+	Timestamp
 }
 
 func (Status) EventType() EventType { return EventTypeStatus }
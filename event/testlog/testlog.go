@@ -24,3 +24,17 @@ func (l *EventLog) Sent() []event.Event {
 	l.eventsL.RUnlock()
 	return events
 }
+
+// Logs returns the Log events sent, without their timestamps, so that they
+// can be compared by origin and payload.
+func (l *EventLog) Logs() []event.Log {
+	logs := []event.Log{}
+	for _, e := range l.Sent() {
+		if log, ok := e.(event.Log); ok {
+			log.Time = 0
+			logs = append(logs, log)
+		}
+	}
+
+	return logs
+}
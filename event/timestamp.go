@@ -0,0 +1,37 @@
+package event
+
+import "reflect"
+
+// Timestamp is embedded by events that are timestamped by the Hub, unless
+// they already have a time
+type Timestamp struct {
+	Time int64 `json:"time,omitempty"`
+}
+
+func (timestamp *Timestamp) stamp(time int64) {
+	if timestamp.Time == 0 {
+		timestamp.Time = time
+	}
+}
+
+// timestamped returns a copy of the event stamped with the given time, if it
+// embeds a Timestamp
+func timestamped(event Event, time int64) Event {
+	if event == nil {
+		return nil
+	}
+
+	copied := reflect.New(reflect.TypeOf(event))
+	copied.Elem().Set(reflect.ValueOf(event))
+
+	stamped, ok := copied.Interface().(interface {
+		stamp(int64)
+	})
+	if !ok {
+		return event
+	}
+
+	stamped.stamp(time)
+
+	return copied.Elem().Interface().(Event)
+}
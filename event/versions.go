@@ -0,0 +1,73 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+)
+
+// versions of the event stream protocol that events can be translated to,
+// oldest first
+var SupportedVersions = []Version{"1.1", CURRENT_VERSION}
+
+type ErrUnsupportedVersion struct {
+	Version Version
+}
+
+func (err ErrUnsupportedVersion) Error() string {
+	supported := make([]string, len(SupportedVersions))
+	for i, version := range SupportedVersions {
+		supported[i] = string(version)
+	}
+
+	return fmt.Sprintf(
+		"unsupported event stream version '%s' (supported: %s)",
+		err.Version,
+		strings.Join(supported, ", "),
+	)
+}
+
+func (version Version) Validate() error {
+	for _, supported := range SupportedVersions {
+		if version == supported {
+			return nil
+		}
+	}
+
+	return ErrUnsupportedVersion{version}
+}
+
+// Translate converts an event to the version's schema. Events that the
+// version does not have are dropped, returning false.
+func (version Version) Translate(ev Event) (Event, bool) {
+	if _, ok := ev.(Version); ok {
+		// the stream may have been recorded with another version
+		return version, true
+	}
+
+	if version != "1.1" {
+		return ev, true
+	}
+
+	// 1.1 had no progress events, and only timed start, finish and status
+	switch e := ev.(type) {
+	case Progress:
+		return nil, false
+	case Log:
+		e.Time = 0
+		return e, true
+	case Error:
+		e.Time = 0
+		return e, true
+	case Initialize:
+		e.Time = 0
+		return e, true
+	case Input:
+		e.Time = 0
+		return e, true
+	case Output:
+		e.Time = 0
+		return e, true
+	}
+
+	return ev, true
+}
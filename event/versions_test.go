@@ -0,0 +1,58 @@
+package event_test
+
+import (
+	"github.com/concourse/turbine"
+	. "github.com/concourse/turbine/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Versions", func() {
+	log := Log{Origin: Origin{Type: OriginTypeRun, Name: "stdout"}, Payload: "hi", Timestamp: Timestamp{Time: 42}}
+	status := Status{Status: turbine.StatusStarted, Timestamp: Timestamp{Time: 42}}
+	progress := Progress{Origin: Origin{Type: OriginTypeInput, Name: "repo"}, Current: 1, Timestamp: Timestamp{Time: 42}}
+
+	Describe("Validate", func() {
+		It("accepts supported versions", func() {
+			Ω(Version("1.1").Validate()).Should(Succeed())
+			Ω(CURRENT_VERSION.Validate()).Should(Succeed())
+		})
+
+		It("rejects unsupported versions", func() {
+			err := Version("0.9").Validate()
+			Ω(err).Should(Equal(ErrUnsupportedVersion{Version("0.9")}))
+			Ω(err.Error()).Should(Equal("unsupported event stream version '0.9' (supported: 1.1, " + string(CURRENT_VERSION) + ")"))
+		})
+	})
+
+	Describe("Translate", func() {
+		translate := func(version Version, ev Event) Event {
+			translated, ok := version.Translate(ev)
+			Ω(ok).Should(BeTrue())
+			return translated
+		}
+
+		It("replaces version events with the version", func() {
+			Ω(translate(Version("1.1"), Version("1.0"))).Should(Equal(Version("1.1")))
+			Ω(translate(CURRENT_VERSION, Version("1.1"))).Should(Equal(CURRENT_VERSION))
+		})
+
+		It("leaves events as they are for the current version", func() {
+			Ω(translate(CURRENT_VERSION, log)).Should(Equal(log))
+			Ω(translate(CURRENT_VERSION, progress)).Should(Equal(progress))
+		})
+
+		Context("to 1.1", func() {
+			It("strips times that 1.1 did not have", func() {
+				Ω(translate(Version("1.1"), log)).Should(Equal(Log{Origin: log.Origin, Payload: "hi"}))
+				Ω(translate(Version("1.1"), status)).Should(Equal(status))
+			})
+
+			It("drops progress events", func() {
+				_, ok := Version("1.1").Translate(progress)
+				Ω(ok).Should(BeFalse())
+			})
+		})
+	})
+})
@@ -1,39 +1,198 @@
 package event
 
 import (
-	"io"
+	"bytes"
+	"sync"
+	"time"
 	"unicode/utf8"
 )
 
-type writer struct {
+// how long output is buffered before being emitted as a Log event
+var LogFlushInterval = 250 * time.Millisecond
+
+// how much output is buffered before being emitted regardless of the interval
+var LogBatchSize = 16 * 1024
+
+// Writer batches output into Log events from an origin. Output is emitted
+// once LogFlushInterval has passed since it was first written, or once
+// LogBatchSize bytes are buffered, in which case it is split after the last
+// complete line. Codepoints split across writes are never emitted in halves,
+// and nor are the given secrets, so that the emitter can mask them; output
+// that may be the start of one is held back until more is written or the
+// writer is closed.
+type Writer struct {
 	emitter Emitter
 	origin  Origin
+	clock   Clock
+
+	// secrets to be masked by the emitter, which must arrive in a single event
+	secrets [][]byte
+
+	buffer []byte
 
-	dangling []byte
+	// closed to cancel the pending timed flush, if any
+	flushCancel chan struct{}
+
+	lock sync.Mutex
 }
 
-func NewWriter(emitter Emitter, origin Origin) io.Writer {
-	return &writer{
+func NewWriter(emitter Emitter, origin Origin, clock Clock, secrets []string) *Writer {
+	writer := &Writer{
 		emitter: emitter,
 		origin:  origin,
+		clock:   clock,
+	}
+
+	for _, secret := range secrets {
+		if secret != "" {
+			writer.secrets = append(writer.secrets, []byte(secret))
+		}
 	}
+
+	return writer
 }
 
-func (writer *writer) Write(data []byte) (int, error) {
-	text := append(writer.dangling, data...)
+func (writer *Writer) Write(data []byte) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.buffer = append(writer.buffer, data...)
+
+	for len(writer.buffer) >= LogBatchSize {
+		end := bytes.LastIndexByte(writer.buffer[:LogBatchSize], '\n') + 1
+		if end == 0 {
+			// no line fits in a batch; split within it
+			end = completeRunes(writer.buffer[:LogBatchSize])
+		}
 
-	checkEncoding, _ := utf8.DecodeLastRune(text)
-	if checkEncoding == utf8.RuneError {
-		writer.dangling = text
-		return len(data), nil
+		if end == 0 {
+			// the batch is too small for even a codepoint
+			end = LogBatchSize
+		}
+
+		// unless the batch is too small for a secret
+		if unsplit := writer.unsplit(end); unsplit > 0 {
+			end = unsplit
+		}
+
+		writer.emit(end)
 	}
 
-	writer.dangling = nil
+	if len(writer.buffer) > 0 && writer.flushCancel == nil {
+		cancel := make(chan struct{})
+		writer.flushCancel = cancel
+
+		go writer.flushAfter(writer.clock.After(LogFlushInterval), cancel)
+	}
+
+	return len(data), nil
+}
+
+// Flush emits all buffered output, except for a trailing incomplete
+// codepoint or possible secret.
+func (writer *Writer) Flush() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.stopTimer()
+	writer.emit(writer.unsplit(completeRunes(writer.buffer)))
+
+	return nil
+}
+
+// Close emits all buffered output. It should be called once nothing more
+// will be written, so that the output is emitted before any later events.
+func (writer *Writer) Close() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.stopTimer()
+	writer.emit(len(writer.buffer))
+
+	return nil
+}
+
+func (writer *Writer) flushAfter(due <-chan time.Time, cancel <-chan struct{}) {
+	select {
+	case <-due:
+	case <-cancel:
+		return
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	// flushed some other way while waiting for the lock
+	select {
+	case <-cancel:
+		return
+	default:
+	}
+
+	writer.flushCancel = nil
+	writer.emit(writer.unsplit(completeRunes(writer.buffer)))
+}
+
+func (writer *Writer) stopTimer() {
+	if writer.flushCancel != nil {
+		close(writer.flushCancel)
+		writer.flushCancel = nil
+	}
+}
+
+// emit sends the first n buffered bytes as a Log event
+func (writer *Writer) emit(n int) {
+	if n == 0 {
+		return
+	}
 
 	writer.emitter.EmitEvent(Log{
-		Payload: string(text),
+		Payload: string(writer.buffer[:n]),
 		Origin:  writer.origin,
 	})
 
-	return len(data), nil
+	writer.buffer = append([]byte{}, writer.buffer[n:]...)
+}
+
+// unsplit moves end back to the start of any secret that it would split,
+// including secrets that the buffered output may go on to complete
+func (writer *Writer) unsplit(end int) int {
+	for moved := true; moved; {
+		moved = false
+
+		for _, secret := range writer.secrets {
+			start := end - len(secret) + 1
+			if start < 0 {
+				start = 0
+			}
+
+			for ; start < end; start++ {
+				rest := writer.buffer[start:]
+				if bytes.HasPrefix(rest, secret) || bytes.HasPrefix(secret, rest) {
+					end = start
+					moved = true
+					break
+				}
+			}
+		}
+	}
+
+	return end
+}
+
+// completeRunes returns the length of data without a trailing incomplete
+// codepoint
+func completeRunes(data []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		start := len(data) - i
+		if utf8.RuneStart(data[start]) {
+			if !utf8.FullRune(data[start:]) {
+				return start
+			}
+
+			break
+		}
+	}
+
+	return len(data)
 }
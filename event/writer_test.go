@@ -1,7 +1,8 @@
 package event_test
 
 import (
-	"io"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,8 +17,12 @@ var _ = Describe("Writer", func() {
 	var (
 		emitter *efakes.FakeEmitter
 		origin  Origin
+		clock   *efakes.FakeClock
+		flushes chan time.Time
 
-		writer io.Writer
+		originalBatchSize int
+
+		writer *Writer
 	)
 
 	BeforeEach(func() {
@@ -28,25 +33,156 @@ var _ = Describe("Writer", func() {
 			Name: "some-source",
 		}
 
-		writer = NewWriter(emitter, origin)
+		flushes = make(chan time.Time)
+
+		clock = new(efakes.FakeClock)
+		clock.AfterReturns(flushes)
+
+		originalBatchSize = LogBatchSize
+
+		writer = NewWriter(emitter, origin, clock, nil)
+	})
+
+	AfterEach(func() {
+		LogBatchSize = originalBatchSize
+	})
+
+	payloads := func() []string {
+		payloads := []string{}
+		for i := 0; i < emitter.EmitEventCallCount(); i++ {
+			payloads = append(payloads, emitter.EmitEventArgsForCall(i).(Log).Payload)
+		}
+
+		return payloads
+	}
+
+	It("batches writes into a single log event", func() {
+		writer.Write([]byte("hello "))
+		writer.Write([]byte("world\n"))
+		Ω(emitter.EmitEventCallCount()).Should(Equal(0))
+
+		writer.Flush()
+
+		Ω(emitter.EmitEventCallCount()).Should(Equal(1))
+
+		log := emitter.EmitEventArgsForCall(0).(Log)
+		Ω(log.Payload).Should(Equal("hello world\n"))
+		Ω(log.Origin).Should(Equal(origin))
+		Ω(log.Time).Should(BeZero())
 	})
 
 	It("does not transmit utf8 codepoints that are split in twain", func() {
 		writer.Write([]byte("hello"))
-		Ω(emitter.EmitEventCallCount()).Should(Equal(1))
-		Ω(emitter.EmitEventArgsForCall(0)).Should(Equal(Log{
-			Payload: "hello",
-			Origin:  origin,
-		}))
+		writer.Flush()
+		Ω(payloads()).Should(Equal([]string{"hello"}))
 
 		writer.Write([]byte(nihongo[:7]))
-		Ω(emitter.EmitEventCallCount()).Should(Equal(1))
+		writer.Flush()
+		Ω(payloads()).Should(Equal([]string{"hello", nihongo[:6]}))
 
 		writer.Write([]byte(nihongo[7:]))
-		Ω(emitter.EmitEventCallCount()).Should(Equal(2))
-		Ω(emitter.EmitEventArgsForCall(1)).Should(Equal(Log{
-			Payload: nihongo,
-			Origin:  origin,
-		}))
+		writer.Flush()
+		Ω(payloads()).Should(Equal([]string{"hello", nihongo[:6], nihongo[6:]}))
+	})
+
+	Context("when the flush interval elapses", func() {
+		It("emits the buffered output", func() {
+			writer.Write([]byte("hello"))
+			Ω(clock.AfterArgsForCall(0)).Should(Equal(LogFlushInterval))
+
+			flushes <- time.Now()
+			Eventually(payloads).Should(Equal([]string{"hello"}))
+
+			writer.Write([]byte(" again"))
+			Ω(clock.AfterCallCount()).Should(Equal(2))
+
+			flushes <- time.Now()
+			Eventually(payloads).Should(Equal([]string{"hello", " again"}))
+		})
+
+		It("does not emit output that was already flushed", func() {
+			writer.Write([]byte("hello"))
+			writer.Flush()
+
+			Consistently(flushes).ShouldNot(BeSent(time.Now()))
+			Ω(payloads()).Should(Equal([]string{"hello"}))
+		})
+	})
+
+	Context("when the batch size is reached", func() {
+		BeforeEach(func() {
+			LogBatchSize = 10
+		})
+
+		It("emits up to the last complete line", func() {
+			writer.Write([]byte("one\ntwo\nthree"))
+			Ω(payloads()).Should(Equal([]string{"one\ntwo\n"}))
+
+			writer.Flush()
+			Ω(payloads()).Should(Equal([]string{"one\ntwo\n", "three"}))
+		})
+
+		It("splits lines longer than a batch", func() {
+			writer.Write([]byte(strings.Repeat("x", 25)))
+			Ω(payloads()).Should(Equal([]string{
+				strings.Repeat("x", 10),
+				strings.Repeat("x", 10),
+			}))
+		})
+
+		It("does not split codepoints", func() {
+			writer.Write([]byte("abcdefgh" + nihongo))
+			Ω(payloads()).Should(Equal([]string{"abcdefgh"}))
+		})
+	})
+
+	Context("when the emitter masks secrets", func() {
+		BeforeEach(func() {
+			writer = NewWriter(NewMaskingEmitter(emitter, []string{"s3cr3t"}), origin, clock, []string{"s3cr3t"})
+		})
+
+		It("does not split them across events", func() {
+			writer.Write([]byte("token: s3c"))
+			writer.Flush()
+			Ω(payloads()).Should(Equal([]string{"token: "}))
+
+			writer.Write([]byte("r3t\n"))
+			writer.Flush()
+			Ω(payloads()).Should(Equal([]string{"token: ", Redacted + "\n"}))
+		})
+
+		It("does not split them between batches", func() {
+			LogBatchSize = 10
+
+			writer.Write([]byte("abcdefs3cr3t\n"))
+			writer.Flush()
+			Ω(payloads()).Should(Equal([]string{"abcdef", Redacted + "\n"}))
+		})
+
+		It("emits output that turned out not to be one", func() {
+			writer.Write([]byte("s3c"))
+			writer.Flush()
+			Ω(payloads()).Should(BeEmpty())
+
+			writer.Write([]byte("ond\n"))
+			writer.Flush()
+			Ω(payloads()).Should(Equal([]string{"s3cond\n"}))
+		})
+	})
+
+	Describe("Close", func() {
+		It("emits everything buffered, including incomplete codepoints", func() {
+			writer.Write([]byte("hello" + nihongo[:7]))
+			writer.Close()
+			Ω(payloads()).Should(Equal([]string{"hello" + nihongo[:7]}))
+		})
+
+		It("emits the start of what may have been a secret", func() {
+			writer = NewWriter(NewMaskingEmitter(emitter, []string{"s3cr3t"}), origin, clock, []string{"s3cr3t"})
+
+			writer.Write([]byte("token: s3c"))
+			writer.Close()
+			Ω(payloads()).Should(Equal([]string{"token: s3c"}))
+		})
 	})
 })
@@ -1,8 +1,8 @@
 package turbine
 
-import garden "github.com/cloudfoundry-incubator/garden/api"
+import "github.com/concourse/turbine/runtime"
 
 type HijackPayload struct {
 	Stdin   []byte
-	TTYSpec *garden.TTYSpec
+	TTYSpec *runtime.TTYSpec
 }
@@ -0,0 +1,305 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vito/go-sse/sse"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/event"
+)
+
+var _ = Describe("Running builds", func() {
+	var build turbine.Build
+
+	BeforeEach(func() {
+		build = turbine.Build{
+			Config: turbine.Config{
+				Image: buildImage,
+				Params: map[string]string{
+					"RESULT": "result",
+				},
+				Run: turbine.RunConfig{
+					Path: "/bin/build",
+					Args: []string{"some-input/some-file", "moved-input/another-file"},
+				},
+				Inputs: []turbine.InputConfig{
+					{Name: "another-input", Path: "moved-input"},
+				},
+			},
+
+			Inputs: []turbine.Input{
+				{
+					Name:     "some-input",
+					Resource: "some-resource",
+					Type:     "raw",
+					Source: turbine.Source{
+						"ref":   "some-ref",
+						"files": map[string]interface{}{"some-file": "hello"},
+					},
+				},
+				{
+					Name:     "another-input",
+					Resource: "another-resource",
+					Type:     "raw",
+					Source: turbine.Source{
+						"ref":   "another-ref",
+						"files": map[string]interface{}{"another-file": "world"},
+					},
+				},
+			},
+
+			Outputs: []turbine.Output{
+				{
+					Name:   "some-output",
+					Type:   "raw",
+					On:     turbine.OutputConditions{turbine.OutputConditionSuccess},
+					Params: turbine.Params{"from": "result"},
+				},
+			},
+		}
+	})
+
+	It("fetches the inputs, runs the build, and performs the outputs", func() {
+		events := runBuild(build)
+
+		Ω(events).Should(ContainElement(event.Input{
+			Input: turbine.Input{
+				Name:     "some-input",
+				Resource: "some-resource",
+				Type:     "raw",
+				Source:   build.Inputs[0].Source,
+				Version:  turbine.Version{"ref": "some-ref"},
+				Metadata: []turbine.MetadataField{{Name: "files", Value: "1"}},
+			},
+		}))
+
+		Ω(events).Should(ContainElement(event.Log{
+			Payload: "fetched 1 files\n",
+			Origin: event.Origin{
+				Type: event.OriginTypeInput,
+				Name: "some-input",
+			},
+		}))
+
+		Ω(events).Should(ContainElement(event.Progress{
+			Message: "fetching",
+			Current: 1,
+			Total:   1,
+			Unit:    "files",
+			Origin: event.Origin{
+				Type: event.OriginTypeInput,
+				Name: "some-input",
+			},
+		}))
+
+		Ω(logOutput(events, event.OriginTypeRun)).Should(Equal(
+			"some-input/some-file: hello\nmoved-input/another-file: world\n",
+		))
+
+		Ω(finish(events).ExitStatus).Should(Equal(0))
+
+		Ω(events).Should(ContainElement(event.Output{
+			Output: turbine.Output{
+				Name:    "some-output",
+				Type:    "raw",
+				On:      turbine.OutputConditions{turbine.OutputConditionSuccess},
+				Params:  turbine.Params{"from": "result"},
+				Version: turbine.Version{"contents": "helloworld"},
+			},
+		}))
+
+		Ω(finalStatus(events)).Should(Equal(turbine.StatusSucceeded))
+	})
+
+	It("destroys the resource containers, leaving only the build's", func() {
+		events := runBuild(build)
+		Ω(finalStatus(events)).Should(Equal(turbine.StatusSucceeded))
+
+		Ω(fakeRuntime.Handles()).Should(HaveLen(1))
+	})
+
+	Context("when the build fails", func() {
+		BeforeEach(func() {
+			build.Config.Params["EXIT_STATUS"] = "1"
+		})
+
+		It("does not perform outputs only run on success", func() {
+			events := runBuild(build)
+
+			Ω(finish(events).ExitStatus).Should(Equal(1))
+
+			for _, e := range events {
+				Ω(e).ShouldNot(BeAssignableToTypeOf(event.Output{}))
+			}
+
+			Ω(finalStatus(events)).Should(Equal(turbine.StatusFailed))
+		})
+	})
+
+	Context("when an input provides the build's config", func() {
+		BeforeEach(func() {
+			build.Config = turbine.Config{}
+			build.Outputs = nil
+
+			build.Inputs[0].ConfigPath = "build.yml"
+			build.Inputs[0].Source["files"] = map[string]interface{}{
+				"some-file": "hello",
+				"build.yml": `---
+image: build-image
+run:
+  path: /bin/build
+  args: [some-input/some-file]
+`,
+			}
+		})
+
+		It("runs the build with it", func() {
+			events := runBuild(build)
+
+			Ω(logOutput(events, event.OriginTypeRun)).Should(Equal("some-input/some-file: hello\n"))
+			Ω(finalStatus(events)).Should(Equal(turbine.StatusSucceeded))
+		})
+	})
+
+	Context("when an input has an unknown type", func() {
+		BeforeEach(func() {
+			build.Inputs[0].Type = "bogus"
+		})
+
+		It("rejects the build", func() {
+			payload, err := json.Marshal(build)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			response, err := client.Post(server.URL+"/builds", "application/json", bytes.NewBuffer(payload))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(response.StatusCode).Should(Equal(422))
+		})
+	})
+})
+
+// runBuild executes the build and returns all of its events
+func runBuild(build turbine.Build) []event.Event {
+	payload, err := json.Marshal(build)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	response, err := client.Post(server.URL+"/builds", "application/json", bytes.NewBuffer(payload))
+	Ω(err).ShouldNot(HaveOccurred())
+
+	defer response.Body.Close()
+
+	Ω(response.StatusCode).Should(Equal(http.StatusCreated))
+
+	var scheduled turbine.Build
+	err = json.NewDecoder(response.Body).Decode(&scheduled)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	eventsResponse, err := client.Get(server.URL + "/builds/" + scheduled.Guid + "/events")
+	Ω(err).ShouldNot(HaveOccurred())
+
+	defer eventsResponse.Body.Close()
+
+	received := make(chan []event.Event, 1)
+
+	go func() {
+		defer GinkgoRecover()
+
+		events := []event.Event{}
+
+		reader := sse.NewReader(eventsResponse.Body)
+
+		for {
+			ev, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+
+			Ω(err).ShouldNot(HaveOccurred())
+
+			e, err := event.ParseEvent(event.EventType(ev.Name), ev.Data)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			events = append(events, withoutTime(e))
+
+			if _, ok := e.(event.End); ok {
+				break
+			}
+		}
+
+		received <- events
+	}()
+
+	var events []event.Event
+	Eventually(received, 5*time.Second).Should(Receive(&events))
+
+	return events
+}
+
+// events are timestamped as they are emitted; strip the times so that they
+// can be compared by their content
+func withoutTime(e event.Event) event.Event {
+	switch timed := e.(type) {
+	case event.Log:
+		Ω(timed.Time).ShouldNot(BeZero())
+		timed.Time = 0
+		return timed
+	case event.Input:
+		Ω(timed.Time).ShouldNot(BeZero())
+		timed.Time = 0
+		return timed
+	case event.Output:
+		Ω(timed.Time).ShouldNot(BeZero())
+		timed.Time = 0
+		return timed
+	case event.Progress:
+		Ω(timed.Time).ShouldNot(BeZero())
+		timed.Time = 0
+		return timed
+	}
+
+	return e
+}
+
+func logOutput(events []event.Event, originType event.OriginType) string {
+	output := ""
+
+	for _, e := range events {
+		log, ok := e.(event.Log)
+		if ok && log.Origin.Type == originType {
+			output += log.Payload
+		}
+	}
+
+	return output
+}
+
+func finish(events []event.Event) event.Finish {
+	for _, e := range events {
+		if finish, ok := e.(event.Finish); ok {
+			return finish
+		}
+	}
+
+	Fail("build did not finish")
+
+	return event.Finish{}
+}
+
+func finalStatus(events []event.Event) turbine.Status {
+	var status turbine.Status
+
+	for _, e := range events {
+		if s, ok := e.(event.Status); ok {
+			status = s.Status
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,53 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/turbine"
+)
+
+var _ = Describe("Checking for versions", func() {
+	It("runs the resource's check script and returns its versions", func() {
+		payload, err := json.Marshal(turbine.Input{
+			Type:   "raw",
+			Source: turbine.Source{"ref": "some-ref"},
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		response, err := client.Post(server.URL+"/checks", "application/json", bytes.NewBuffer(payload))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		defer response.Body.Close()
+
+		Ω(response.StatusCode).Should(Equal(http.StatusOK))
+
+		var versions []turbine.Version
+		err = json.NewDecoder(response.Body).Decode(&versions)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(versions).Should(Equal([]turbine.Version{{"ref": "some-ref"}}))
+
+		Ω(fakeRuntime.Handles()).Should(BeEmpty())
+	})
+
+	Context("when the check outlives its resource type's timeout", func() {
+		It("returns 504 and destroys the container", func() {
+			payload, err := json.Marshal(turbine.Input{Type: "hanging"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			response, err := client.Post(server.URL+"/checks", "application/json", bytes.NewBuffer(payload))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			defer response.Body.Close()
+
+			Ω(response.StatusCode).Should(Equal(http.StatusGatewayTimeout))
+
+			Ω(fakeRuntime.Handles()).Should(BeEmpty())
+		})
+	})
+})
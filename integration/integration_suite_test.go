@@ -0,0 +1,102 @@
+package integration_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"testing"
+
+	"github.com/concourse/turbine/api"
+	"github.com/concourse/turbine/builder"
+	"github.com/concourse/turbine/builder/inputs"
+	"github.com/concourse/turbine/builder/outputs"
+	"github.com/concourse/turbine/config"
+	"github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/runtime"
+	"github.com/concourse/turbine/runtime/fakeruntime"
+	gardenruntime "github.com/concourse/turbine/runtime/garden"
+	"github.com/concourse/turbine/scheduler"
+)
+
+var depotDir string
+var fakeRuntime *fakeruntime.Runtime
+var drain chan struct{}
+
+var server *httptest.Server
+var client *http.Client
+
+var _ = BeforeEach(func() {
+	var err error
+
+	depotDir, err = ioutil.TempDir("", "turbine-integration")
+	Ω(err).ShouldNot(HaveOccurred())
+
+	fakeRuntime = fakeruntime.New(depotDir)
+	registerRawResource(fakeRuntime)
+	registerHangingResource(fakeRuntime)
+	registerBuildImage(fakeRuntime)
+
+	// go through runtime/garden, as turbine does against a Garden server
+	serve(gardenruntime.New(fakeRuntime.GardenClient()))
+})
+
+// serve starts the server with real components, running containers on the
+// given runtime
+func serve(rt runtime.Runtime) {
+	resourceTypes := config.ResourceTypes{
+		{Name: "raw", Image: rawResourceImage},
+		{Name: "hanging", Image: hangingResourceImage, Timeout: 100 * time.Millisecond},
+	}
+
+	logger := lagertest.NewTestLogger("integration")
+
+	tracker := resource.NewTracker(resourceTypes, rt, nil)
+
+	clock := scheduler.NewClock()
+
+	builder := builder.NewBuilder(
+		rt,
+		inputs.NewParallelFetcher(tracker, 0, clock),
+		outputs.NewParallelPerformer(tracker, clock),
+		clock,
+	)
+
+	scheduler := scheduler.NewScheduler(logger, builder, clock)
+
+	drain = make(chan struct{})
+
+	handler, err := api.New(
+		logger,
+		scheduler,
+		tracker,
+		resourceTypes,
+		0,
+		0,
+		clock,
+		"http://some-turbine",
+		drain,
+	)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	server = httptest.NewServer(handler)
+	client = &http.Client{
+		Transport: &http.Transport{},
+	}
+}
+
+var _ = AfterEach(func() {
+	server.Close()
+	os.RemoveAll(depotDir)
+})
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Integration Suite")
+}
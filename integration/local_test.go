@@ -0,0 +1,71 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/runtime/local"
+	"github.com/concourse/turbine/runtime/tarfs"
+)
+
+// the fake runtime's scripts stand in for executables; these run the build
+// as a real process, via the local runtime
+var _ = Describe("Running builds as real processes", func() {
+	var rootFSPath string
+
+	BeforeEach(func() {
+		if os.Getuid() != 0 && goruntime.GOOS != "linux" {
+			Skip("running processes without root requires user namespaces")
+		}
+
+		// must be static to run in a bare chroot
+		helloPath, err := gexec.BuildWithEnvironment(
+			"github.com/concourse/turbine/runtime/local/fixtures/hello",
+			[]string{"CGO_ENABLED=0"},
+		)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		rootFSPath = filepath.Join(depotDir, "rootfs")
+
+		// the binary is built into a directory of its own
+		err = tarfs.Copy(filepath.Dir(helloPath), filepath.Join(rootFSPath, "bin"))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		server.Close()
+		serve(local.New(filepath.Join(depotDir, "containers")))
+	})
+
+	AfterEach(func() {
+		gexec.CleanupBuildArtifacts()
+	})
+
+	It("runs the build and reports its output and exit status", func() {
+		events := runBuild(turbine.Build{
+			Config: turbine.Config{
+				Image: rootFSPath,
+				Params: map[string]string{
+					"EXIT_STATUS": "3",
+				},
+				Run: turbine.RunConfig{
+					Path: "/bin/hello",
+					Args: []string{"some-arg"},
+				},
+			},
+		})
+
+		output := logOutput(events, event.OriginTypeRun)
+		Ω(output).Should(ContainSubstring("args: [some-arg]\n"))
+		Ω(output).Should(ContainSubstring("EXIT_STATUS=3"))
+		Ω(output).Should(ContainSubstring("hello from stderr\n"))
+
+		Ω(finish(events).ExitStatus).Should(Equal(3))
+		Ω(finalStatus(events)).Should(Equal(turbine.StatusFailed))
+	})
+})
@@ -0,0 +1,178 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/runtime/fakeruntime"
+)
+
+const rawResourceImage = "raw-resource-image"
+const hangingResourceImage = "hanging-resource-image"
+const buildImage = "build-image"
+
+// a resource whose source is the files it contains, keyed by path, and whose
+// version is its "ref"
+func registerRawResource(fakeRuntime *fakeruntime.Runtime) {
+	fakeRuntime.Register(rawResourceImage, "/opt/resource/check", func(p *fakeruntime.Process) int {
+		var request struct {
+			Source turbine.Source `json:"source"`
+		}
+
+		err := json.NewDecoder(p.Stdin).Decode(&request)
+		if err != nil {
+			fmt.Fprintf(p.Stderr, "bad request: %s\n", err)
+			return 1
+		}
+
+		return respond(p, []turbine.Version{{"ref": request.Source["ref"]}})
+	})
+
+	fakeRuntime.Register(rawResourceImage, "/opt/resource/in", func(p *fakeruntime.Process) int {
+		var request struct {
+			Source struct {
+				Ref   string            `json:"ref"`
+				Files map[string]string `json:"files"`
+			} `json:"source"`
+		}
+
+		err := json.NewDecoder(p.Stdin).Decode(&request)
+		if err != nil {
+			fmt.Fprintf(p.Stderr, "bad request: %s\n", err)
+			return 1
+		}
+
+		destination := p.Spec.Args[0]
+
+		for name, contents := range request.Source.Files {
+			err := writeFile(p.Path(filepath.Join(destination, name)), contents)
+			if err != nil {
+				fmt.Fprintf(p.Stderr, "failed to write %s: %s\n", name, err)
+				return 1
+			}
+		}
+
+		fmt.Fprintf(p.Stderr, "fetched %d files\n", len(request.Source.Files))
+
+		progressFile := scriptEnv(p)["TURBINE_PROGRESS_FILE"]
+		if progressFile != "" {
+			err := writeFile(p.Path(progressFile), fmt.Sprintf(
+				`{"message":"fetching","current":%d,"total":%d,"unit":"files"}`+"\n",
+				len(request.Source.Files),
+				len(request.Source.Files),
+			))
+			if err != nil {
+				fmt.Fprintf(p.Stderr, "failed to report progress: %s\n", err)
+				return 1
+			}
+		}
+
+		return respond(p, map[string]interface{}{
+			"version": turbine.Version{"ref": request.Source.Ref},
+			"metadata": []turbine.MetadataField{
+				{Name: "files", Value: strconv.Itoa(len(request.Source.Files))},
+			},
+		})
+	})
+
+	// reports the contents of the file at params.from as the new version
+	fakeRuntime.Register(rawResourceImage, "/opt/resource/out", func(p *fakeruntime.Process) int {
+		var request struct {
+			Params struct {
+				From string `json:"from"`
+			} `json:"params"`
+		}
+
+		err := json.NewDecoder(p.Stdin).Decode(&request)
+		if err != nil {
+			fmt.Fprintf(p.Stderr, "bad request: %s\n", err)
+			return 1
+		}
+
+		contents, err := ioutil.ReadFile(p.Path(filepath.Join(p.Spec.Args[0], request.Params.From)))
+		if err != nil {
+			fmt.Fprintf(p.Stderr, "failed to read %s: %s\n", request.Params.From, err)
+			return 1
+		}
+
+		return respond(p, map[string]interface{}{
+			"version": turbine.Version{"contents": string(contents)},
+		})
+	})
+}
+
+// a resource whose check never finishes until its container is stopped
+func registerHangingResource(fakeRuntime *fakeruntime.Runtime) {
+	fakeRuntime.Register(hangingResourceImage, "/opt/resource/check", func(p *fakeruntime.Process) int {
+		<-p.Stopped
+		return 137
+	})
+}
+
+// /bin/build prints the file at each given path, writes their concatenation
+// to the path in $RESULT, and exits with $EXIT_STATUS
+func registerBuildImage(fakeRuntime *fakeruntime.Runtime) {
+	fakeRuntime.Register(buildImage, "/bin/build", func(p *fakeruntime.Process) int {
+		env := scriptEnv(p)
+
+		result := ""
+
+		for _, path := range p.Spec.Args {
+			contents, err := ioutil.ReadFile(p.Path(filepath.Join(p.Spec.Dir, path)))
+			if err != nil {
+				fmt.Fprintf(p.Stderr, "failed to read %s: %s\n", path, err)
+				return 1
+			}
+
+			fmt.Fprintf(p.Stdout, "%s: %s\n", path, contents)
+
+			result += string(contents)
+		}
+
+		if env["RESULT"] != "" {
+			err := writeFile(p.Path(filepath.Join(p.Spec.Dir, env["RESULT"])), result)
+			if err != nil {
+				fmt.Fprintf(p.Stderr, "failed to write result: %s\n", err)
+				return 1
+			}
+		}
+
+		status, _ := strconv.Atoi(env["EXIT_STATUS"])
+		return status
+	})
+}
+
+func scriptEnv(p *fakeruntime.Process) map[string]string {
+	env := map[string]string{}
+	for _, pair := range p.Spec.Env {
+		segs := strings.SplitN(pair, "=", 2)
+		env[segs[0]] = segs[1]
+	}
+
+	return env
+}
+
+func respond(p *fakeruntime.Process, response interface{}) int {
+	err := json.NewEncoder(p.Stdout).Encode(response)
+	if err != nil {
+		fmt.Fprintf(p.Stderr, "failed to respond: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func writeFile(path string, contents string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, []byte(contents), 0644)
+}
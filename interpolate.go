@@ -0,0 +1,285 @@
+package turbine
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var referenceRegexp = regexp.MustCompile(`\(\((inputs\.[^()]*)\)\)`)
+var outputReferenceRegexp = regexp.MustCompile(`\(\((outputs\.[^()]*)\)\)`)
+
+type UnresolvedReferenceError struct {
+	Reference string
+	Reason    string
+}
+
+func (err UnresolvedReferenceError) Error() string {
+	return fmt.Sprintf("cannot resolve ((%s)): %s", err.Reference, err.Reason)
+}
+
+// referable is the part of an input or output that can be referred to
+type referable struct {
+	Version  Version
+	Metadata []MetadataField
+}
+
+// references resolves ((<kind>s.<name>.<version|metadata>.<key>))
+type references struct {
+	kind    string
+	pattern *regexp.Regexp
+	byName  map[string]referable
+}
+
+func inputReferences(inputs []Input) references {
+	byName := make(map[string]referable, len(inputs))
+	for _, input := range inputs {
+		byName[input.Name] = referable{input.Version, input.Metadata}
+	}
+
+	return references{kind: "input", pattern: referenceRegexp, byName: byName}
+}
+
+func outputReferences(outputs []Output) references {
+	byName := make(map[string]referable, len(outputs))
+	for _, output := range outputs {
+		byName[output.Name] = referable{output.Version, output.Metadata}
+	}
+
+	return references{kind: "output", pattern: outputReferenceRegexp, byName: byName}
+}
+
+// Interpolate resolves references to the given inputs' versions and metadata
+// in the config's params and run args, e.g. ((inputs.repo.version.ref)) or
+// ((inputs.repo.metadata.author)).
+func (config Config) Interpolate(inputs []Input) (Config, error) {
+	refs := inputReferences(inputs)
+
+	var err error
+
+	config.Params, err = interpolateParams(config.Params, refs)
+	if err != nil {
+		return Config{}, err
+	}
+
+	config.SecretParams, err = interpolateParams(config.SecretParams, refs)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if config.Run.Args != nil {
+		args := make([]string, len(config.Run.Args))
+		for i, arg := range config.Run.Args {
+			args[i], err = refs.interpolate(arg)
+			if err != nil {
+				return Config{}, err
+			}
+		}
+
+		config.Run.Args = args
+	}
+
+	return config, nil
+}
+
+// Interpolate resolves references to the versions and metadata of outputs
+// that have already been performed in the output's params and source, e.g.
+// ((outputs.bump.version.number)).
+func (output Output) Interpolate(performed []Output) (Output, error) {
+	refs := outputReferences(performed)
+
+	params, err := refs.interpolateValue(map[string]interface{}(output.Params))
+	if err != nil {
+		return Output{}, err
+	}
+
+	source, err := refs.interpolateValue(map[string]interface{}(output.Source))
+	if err != nil {
+		return Output{}, err
+	}
+
+	if output.Params != nil {
+		output.Params = Params(params.(map[string]interface{}))
+	}
+
+	if output.Source != nil {
+		output.Source = Source(source.(map[string]interface{}))
+	}
+
+	return output, nil
+}
+
+// OutputReferences returns the names of the outputs referred to in the
+// output's params and source.
+func (output Output) OutputReferences() []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	collect := func(str string) {
+		for _, match := range outputReferenceRegexp.FindAllStringSubmatch(str, -1) {
+			segments := strings.SplitN(match[1], ".", 3)
+			if len(segments) < 2 || seen[segments[1]] {
+				continue
+			}
+
+			seen[segments[1]] = true
+			names = append(names, segments[1])
+		}
+	}
+
+	walkStrings(map[string]interface{}(output.Params), collect)
+	walkStrings(map[string]interface{}(output.Source), collect)
+
+	return names
+}
+
+func interpolateParams(params map[string]string, refs references) (map[string]string, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	interpolated := make(map[string]string, len(params))
+	for name, value := range params {
+		resolved, err := refs.interpolate(value)
+		if err != nil {
+			return nil, err
+		}
+
+		interpolated[name] = resolved
+	}
+
+	return interpolated, nil
+}
+
+// interpolateValue resolves references in every string nested within the
+// value, returning a copy.
+func (refs references) interpolateValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return refs.interpolate(v)
+
+	case map[string]interface{}:
+		if v == nil {
+			return v, nil
+		}
+
+		interpolated := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			resolved, err := refs.interpolateValue(val)
+			if err != nil {
+				return nil, err
+			}
+
+			interpolated[key] = resolved
+		}
+
+		return interpolated, nil
+
+	case []interface{}:
+		interpolated := make([]interface{}, len(v))
+		for i, val := range v {
+			resolved, err := refs.interpolateValue(val)
+			if err != nil {
+				return nil, err
+			}
+
+			interpolated[i] = resolved
+		}
+
+		return interpolated, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func walkStrings(value interface{}, visit func(string)) {
+	switch v := value.(type) {
+	case string:
+		visit(v)
+	case map[string]interface{}:
+		for _, val := range v {
+			walkStrings(val, visit)
+		}
+	case []interface{}:
+		for _, val := range v {
+			walkStrings(val, visit)
+		}
+	}
+}
+
+func (refs references) interpolate(str string) (string, error) {
+	var resolveErr error
+
+	interpolated := refs.pattern.ReplaceAllStringFunc(str, func(match string) string {
+		reference := refs.pattern.FindStringSubmatch(match)[1]
+
+		value, err := refs.resolve(reference)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return value
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return interpolated, nil
+}
+
+func (refs references) resolve(reference string) (string, error) {
+	kind := refs.kind
+
+	// <kind>s.<name>.<version|metadata>.<key>
+	segments := strings.SplitN(reference, ".", 4)
+	if len(segments) != 4 {
+		return "", UnresolvedReferenceError{reference, fmt.Sprintf("expected %[1]ss.<name>.version.<key> or %[1]ss.<name>.metadata.<field>", kind)}
+	}
+
+	ref, found := refs.byName[segments[1]]
+	if !found {
+		return "", UnresolvedReferenceError{reference, "unknown " + kind}
+	}
+
+	key := segments[3]
+
+	switch segments[2] {
+	case "version":
+		value, found := ref.Version[key]
+		if !found {
+			return "", UnresolvedReferenceError{reference, kind + " version has no such key"}
+		}
+
+		return VersionValueString(value)
+
+	case "metadata":
+		for _, field := range ref.Metadata {
+			if field.Name == key {
+				return field.Value, nil
+			}
+		}
+
+		return "", UnresolvedReferenceError{reference, kind + " metadata has no such field"}
+
+	default:
+		return "", UnresolvedReferenceError{reference, "expected 'version' or 'metadata'"}
+	}
+}
+
+// VersionValueString renders a version value as a string; strings are
+// returned as-is, anything else as JSON.
+func VersionValueString(value interface{}) (string, error) {
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(payload), nil
+}
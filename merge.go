@@ -5,21 +5,8 @@ func (a Config) Merge(b Config) Config {
 		a.Image = b.Image
 	}
 
-	if len(a.Params) > 0 {
-		newParams := map[string]string{}
-
-		for k, v := range a.Params {
-			newParams[k] = v
-		}
-
-		for k, v := range b.Params {
-			newParams[k] = v
-		}
-
-		a.Params = newParams
-	} else {
-		a.Params = b.Params
-	}
+	a.Params = mergeParams(a.Params, b.Params)
+	a.SecretParams = mergeParams(a.SecretParams, b.SecretParams)
 
 	if len(b.Inputs) != 0 {
 		a.Inputs = b.Inputs
@@ -31,3 +18,21 @@ func (a Config) Merge(b Config) Config {
 
 	return a
 }
+
+func mergeParams(a, b map[string]string) map[string]string {
+	if len(a) == 0 {
+		return b
+	}
+
+	newParams := map[string]string{}
+
+	for k, v := range a {
+		newParams[k] = v
+	}
+
+	for k, v := range b {
+		newParams[k] = v
+	}
+
+	return newParams
+}
@@ -0,0 +1,92 @@
+package turbine
+
+import "path"
+
+// what turbine would do when running a build
+type BuildPlan struct {
+	// effective config, after merging in config provided by inputs
+	Config Config `json:"config"`
+
+	Inputs  []InputPlan `json:"inputs"`
+	Outputs OutputsPlan `json:"outputs"`
+}
+
+type InputPlan struct {
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	Type     string `json:"type"`
+
+	// set if the input was fetched to determine the plan
+	Version Version `json:"version,omitempty"`
+
+	// absolute path the input will be placed at in the build's container
+	Destination string `json:"destination"`
+}
+
+// names of outputs that would be performed for each result
+type OutputsPlan struct {
+	OnSuccess []string `json:"on_success"`
+	OnFailure []string `json:"on_failure"`
+	OnAborted []string `json:"on_aborted"`
+	OnErrored []string `json:"on_errored"`
+}
+
+// Plan describes what running the build would do. The build's config should
+// already have config provided by its inputs merged in.
+func (build Build) Plan(sourcesDir string) BuildPlan {
+	destinations := map[string]string{}
+	for _, input := range build.Config.Inputs {
+		if input.Path != "" {
+			destinations[input.Name] = input.Path
+		}
+	}
+
+	inputs := make([]InputPlan, len(build.Inputs))
+	for i, input := range build.Inputs {
+		destination, found := destinations[input.Name]
+		if !found {
+			// input location defaults to its name
+			destination = input.Name
+		}
+
+		inputs[i] = InputPlan{
+			Name:        input.Name,
+			Resource:    input.Resource,
+			Type:        input.Type,
+			Version:     input.Version,
+			Destination: path.Join(sourcesDir, destination),
+		}
+	}
+
+	outputs := OutputsPlan{
+		OnSuccess: []string{},
+		OnFailure: []string{},
+		OnAborted: []string{},
+		OnErrored: []string{},
+	}
+
+	for _, output := range build.Outputs {
+		if output.On.SatisfiedBy(StatusSucceeded, 0) {
+			outputs.OnSuccess = append(outputs.OnSuccess, output.Name)
+		}
+
+		// with exit status ranges, an output may only run for some failures
+		if output.On.satisfiedByAnyFailure() {
+			outputs.OnFailure = append(outputs.OnFailure, output.Name)
+		}
+
+		if output.On.SatisfiedBy(StatusAborted, 0) {
+			outputs.OnAborted = append(outputs.OnAborted, output.Name)
+		}
+
+		if output.On.SatisfiedBy(StatusErrored, 0) {
+			outputs.OnErrored = append(outputs.OnErrored, output.Name)
+		}
+	}
+
+	return BuildPlan{
+		Config:  build.Config.Redacted(),
+		Inputs:  inputs,
+		Outputs: outputs,
+	}
+}
@@ -0,0 +1,36 @@
+package turbine
+
+import "sort"
+
+// Redacted returns the build with its secret params omitted, making it safe
+// to emit in events, snapshots, and logs.
+func (build Build) Redacted() Build {
+	build.Config = build.Config.Redacted()
+	return build
+}
+
+func (config Config) Redacted() Config {
+	config.SecretParams = nil
+	return config
+}
+
+// Secrets returns the values of the config's secret params, longest first,
+// so that output can be masked.
+func (config Config) Secrets() []string {
+	secrets := []string{}
+	for _, v := range config.SecretParams {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+
+	sort.Sort(byLength(secrets))
+
+	return secrets
+}
+
+type byLength []string
+
+func (s byLength) Len() int           { return len(s) }
+func (s byLength) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
+func (s byLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,48 @@
+package resource
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// boundedBuffer keeps the first limit bytes written to it, counting but
+// discarding the rest, so that a noisy script cannot exhaust memory.
+type boundedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+	total int64
+}
+
+func newBoundedBuffer(limit int) *boundedBuffer {
+	return &boundedBuffer{limit: limit}
+}
+
+func (buffer *boundedBuffer) Write(data []byte) (int, error) {
+	buffer.total += int64(len(data))
+
+	room := buffer.limit - buffer.buf.Len()
+	if room > len(data) {
+		room = len(data)
+	}
+
+	if room > 0 {
+		buffer.buf.Write(data[:room])
+	}
+
+	return len(data), nil
+}
+
+// Total is the number of bytes written, including those discarded.
+func (buffer *boundedBuffer) Total() int64 {
+	return buffer.total
+}
+
+// String returns what was kept, noting how much was discarded.
+func (buffer *boundedBuffer) String() string {
+	truncated := buffer.total - int64(buffer.buf.Len())
+	if truncated == 0 {
+		return buffer.buf.String()
+	}
+
+	return fmt.Sprintf("%s\n... (%d bytes truncated)", buffer.buf.String(), truncated)
+}
@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/concourse/turbine"
+)
+
+// build config as written in an input's config file, where params may be
+// arbitrary YAML values rather than strings
+type buildConfigFile struct {
+	Image        string                 `yaml:"image"`
+	Params       map[string]interface{} `yaml:"params"`
+	SecretParams map[string]interface{} `yaml:"secret_params"`
+	Run          turbine.RunConfig      `yaml:"run"`
+	Inputs       []turbine.InputConfig  `yaml:"inputs"`
+}
+
+func (file buildConfigFile) Config() (turbine.Config, error) {
+	params, err := renderParams(file.Params)
+	if err != nil {
+		return turbine.Config{}, err
+	}
+
+	secretParams, err := renderParams(file.SecretParams)
+	if err != nil {
+		return turbine.Config{}, err
+	}
+
+	return turbine.Config{
+		Image:        file.Image,
+		Params:       params,
+		SecretParams: secretParams,
+		Run:          file.Run,
+		Inputs:       file.Inputs,
+	}, nil
+}
+
+func renderParams(params map[string]interface{}) (map[string]string, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	rendered := make(map[string]string, len(params))
+
+	for name, value := range params {
+		env, err := renderParam(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for param '%s': %s", name, err)
+		}
+
+		rendered[name] = env
+	}
+
+	return rendered, nil
+}
+
+// strings are used as-is and other scalars are formatted; maps and lists are
+// rendered as JSON, which sorts map keys so the result is deterministic
+func renderParam(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[interface{}]interface{}, map[string]interface{}, []interface{}:
+		payload, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return "", err
+		}
+
+		return string(payload), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// YAML maps may have non-string keys, which encoding/json refuses
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			converted[fmt.Sprintf("%v", key)] = jsonValue(val)
+		}
+
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			converted[key] = jsonValue(val)
+		}
+
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, val := range v {
+			converted[i] = jsonValue(val)
+		}
+
+		return converted
+	default:
+		return v
+	}
+}
@@ -0,0 +1,140 @@
+package resource
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/concourse/turbine"
+)
+
+// Checker runs checks, sharing the result of one check among all identical
+// requests made while it runs and for a while afterwards.
+type Checker interface {
+	Check(turbine.Input) ([]turbine.Version, error)
+}
+
+type cachingChecker struct {
+	tracker  Tracker
+	cacheTTL time.Duration
+	abort    <-chan struct{}
+
+	calls  map[string]*checkCall
+	callsL *sync.Mutex
+}
+
+type checkCall struct {
+	done chan struct{}
+
+	// set before done is closed
+	versions []turbine.Version
+	err      error
+	finished time.Time
+}
+
+// NewChecker returns a Checker that caches successful results for the
+// given duration; failures are only shared with concurrent requests.
+func NewChecker(tracker Tracker, cacheTTL time.Duration, abort <-chan struct{}) Checker {
+	return &cachingChecker{
+		tracker:  tracker,
+		cacheTTL: cacheTTL,
+		abort:    abort,
+
+		calls:  make(map[string]*checkCall),
+		callsL: new(sync.Mutex),
+	}
+}
+
+func (checker *cachingChecker) Check(input turbine.Input) ([]turbine.Version, error) {
+	key, keyed := checkKey(input)
+	if !keyed {
+		// checks that can't be identified can't be shared
+		return checker.check(input)
+	}
+
+	checker.callsL.Lock()
+
+	call, found := checker.calls[key]
+	if !found || call.expired(checker.cacheTTL) {
+		call = &checkCall{done: make(chan struct{})}
+		checker.calls[key] = call
+
+		checker.pruneExpired()
+		checker.callsL.Unlock()
+
+		checker.run(key, call, input)
+	} else {
+		checker.callsL.Unlock()
+	}
+
+	<-call.done
+
+	return call.versions, call.err
+}
+
+func (checker *cachingChecker) run(key string, call *checkCall, input turbine.Input) {
+	// waiters must be released however the check ends
+	defer close(call.done)
+
+	defer func() {
+		if r := recover(); r != nil {
+			call.versions = nil
+			call.err = fmt.Errorf("check panicked: %v", r)
+		}
+
+		call.finished = time.Now()
+
+		if call.err != nil || checker.cacheTTL == 0 {
+			checker.callsL.Lock()
+			if checker.calls[key] == call {
+				delete(checker.calls, key)
+			}
+			checker.callsL.Unlock()
+		}
+	}()
+
+	call.versions, call.err = checker.check(input)
+}
+
+func (checker *cachingChecker) check(input turbine.Input) ([]turbine.Version, error) {
+	resource, err := checker.tracker.InitForCheck(input.Type, input.Source, checker.abort)
+	if err != nil {
+		return nil, err
+	}
+
+	defer checker.tracker.Release(resource)
+
+	return resource.Check(input)
+}
+
+// must be called with callsL held
+func (checker *cachingChecker) pruneExpired() {
+	for key, call := range checker.calls {
+		if call.expired(checker.cacheTTL) {
+			delete(checker.calls, key)
+		}
+	}
+}
+
+// a call expires once it has finished and its result is older than the ttl;
+// calls still running never expire
+func (call *checkCall) expired(ttl time.Duration) bool {
+	select {
+	case <-call.done:
+		return time.Since(call.finished) >= ttl
+	default:
+		return false
+	}
+}
+
+// identical checks have the same type, source, version, and timeout; a
+// check that timed out must not be shared with one that would have waited
+// longer
+func checkKey(input turbine.Input) (string, bool) {
+	return sourceKey(turbine.Source{
+		"type":    input.Type,
+		"source":  input.Source,
+		"version": input.Version,
+		"timeout": input.Timeout,
+	})
+}
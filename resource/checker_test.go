@@ -0,0 +1,232 @@
+package resource_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/turbine"
+	. "github.com/concourse/turbine/resource"
+	rfakes "github.com/concourse/turbine/resource/fakes"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		tracker      *rfakes.FakeTracker
+		fakeResource *rfakes.FakeResource
+		cacheTTL     time.Duration
+		drain        chan struct{}
+
+		input turbine.Input
+
+		checker Checker
+	)
+
+	versions := []turbine.Version{{"ref": "a"}, {"ref": "b"}}
+
+	BeforeEach(func() {
+		tracker = new(rfakes.FakeTracker)
+		fakeResource = new(rfakes.FakeResource)
+		tracker.InitForCheckReturns(fakeResource, nil)
+
+		fakeResource.CheckReturns(versions, nil)
+
+		cacheTTL = time.Minute
+		drain = make(chan struct{})
+
+		input = turbine.Input{
+			Type:    "git",
+			Source:  turbine.Source{"uri": "example.com", "branch": "master"},
+			Version: turbine.Version{"ref": "a"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		checker = NewChecker(tracker, cacheTTL, drain)
+	})
+
+	It("checks with a resource of the input's type and source and releases it", func() {
+		checked, err := checker.Check(input)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(checked).Should(Equal(versions))
+
+		typ, source, abort := tracker.InitForCheckArgsForCall(0)
+		Ω(typ).Should(Equal("git"))
+		Ω(source).Should(Equal(input.Source))
+		Ω(abort).Should(Equal((<-chan struct{})(drain)))
+
+		Ω(fakeResource.CheckArgsForCall(0)).Should(Equal(input))
+
+		Ω(tracker.ReleaseCallCount()).Should(Equal(1))
+		Ω(tracker.ReleaseArgsForCall(0)).Should(Equal(fakeResource))
+	})
+
+	Context("when identical checks are made while one is running", func() {
+		var finishCheck chan struct{}
+
+		BeforeEach(func() {
+			finishCheck = make(chan struct{})
+
+			fakeResource.CheckStub = func(turbine.Input) ([]turbine.Version, error) {
+				<-finishCheck
+				return versions, nil
+			}
+		})
+
+		It("runs the check once and returns its result to everyone", func() {
+			results := make(chan []turbine.Version, 3)
+
+			for i := 0; i < 3; i++ {
+				go func(input turbine.Input) {
+					defer GinkgoRecover()
+
+					checked, err := checker.Check(input)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					results <- checked
+				}(input)
+
+				// the same source, built separately
+				input.Source = turbine.Source{"branch": "master", "uri": "example.com"}
+			}
+
+			Eventually(fakeResource.CheckCallCount).Should(Equal(1))
+			Consistently(results).ShouldNot(Receive())
+
+			close(finishCheck)
+
+			for i := 0; i < 3; i++ {
+				Eventually(results).Should(Receive(Equal(versions)))
+			}
+
+			Ω(tracker.InitForCheckCallCount()).Should(Equal(1))
+		})
+	})
+
+	Context("when the checks differ", func() {
+		It("runs each of them", func() {
+			_, err := checker.Check(input)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			input.Version = turbine.Version{"ref": "b"}
+
+			_, err = checker.Check(input)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeResource.CheckCallCount()).Should(Equal(2))
+		})
+
+		It("runs checks with different timeouts separately", func() {
+			_, err := checker.Check(input)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			input.Timeout = turbine.Duration(time.Hour)
+
+			_, err = checker.Check(input)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeResource.CheckCallCount()).Should(Equal(2))
+		})
+	})
+
+	Context("when the check panics", func() {
+		BeforeEach(func() {
+			fakeResource.CheckStub = func(turbine.Input) ([]turbine.Version, error) {
+				panic("oh no!")
+			}
+		})
+
+		It("returns an error to everyone waiting and does not cache it", func() {
+			_, err := checker.Check(input)
+			Ω(err).Should(MatchError("check panicked: oh no!"))
+
+			_, err = checker.Check(input)
+			Ω(err).Should(HaveOccurred())
+
+			Ω(fakeResource.CheckCallCount()).Should(Equal(2))
+			Ω(tracker.ReleaseCallCount()).Should(Equal(2))
+		})
+	})
+
+	Describe("caching", func() {
+		It("returns the previous result for identical checks", func() {
+			_, err := checker.Check(input)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			checked, err := checker.Check(input)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(checked).Should(Equal(versions))
+
+			Ω(fakeResource.CheckCallCount()).Should(Equal(1))
+		})
+
+		Context("once the result expires", func() {
+			BeforeEach(func() {
+				cacheTTL = 10 * time.Millisecond
+			})
+
+			It("checks again", func() {
+				_, err := checker.Check(input)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				time.Sleep(cacheTTL)
+
+				_, err = checker.Check(input)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(fakeResource.CheckCallCount()).Should(Equal(2))
+			})
+		})
+
+		Context("with no TTL", func() {
+			BeforeEach(func() {
+				cacheTTL = 0
+			})
+
+			It("does not cache", func() {
+				_, err := checker.Check(input)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = checker.Check(input)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(fakeResource.CheckCallCount()).Should(Equal(2))
+			})
+		})
+
+		Context("when the check fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeResource.CheckReturns(nil, disaster)
+			})
+
+			It("does not cache the failure", func() {
+				_, err := checker.Check(input)
+				Ω(err).Should(Equal(disaster))
+
+				_, err = checker.Check(input)
+				Ω(err).Should(Equal(disaster))
+
+				Ω(fakeResource.CheckCallCount()).Should(Equal(2))
+			})
+		})
+	})
+
+	Context("when initializing the resource fails", func() {
+		disaster := errors.New("oh no!")
+
+		BeforeEach(func() {
+			tracker.InitForCheckReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			_, err := checker.Check(input)
+			Ω(err).Should(Equal(disaster))
+
+			Ω(tracker.ReleaseCallCount()).Should(BeZero())
+		})
+	})
+})
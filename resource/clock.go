@@ -0,0 +1,24 @@
+package resource
+
+import "time"
+
+// Clock tells the pool when to maintain itself and how long containers have
+// been idle, and paces watched checks
+type Clock interface {
+	CurrentTime() time.Time
+	After(time.Duration) <-chan time.Time
+}
+
+func NewClock() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) CurrentTime() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
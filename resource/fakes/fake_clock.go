@@ -0,0 +1,84 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/concourse/turbine/resource"
+)
+
+type FakeClock struct {
+	CurrentTimeStub        func() time.Time
+	currentTimeMutex       sync.RWMutex
+	currentTimeArgsForCall []struct{}
+	currentTimeReturns     struct {
+		result1 time.Time
+	}
+	AfterStub        func(time.Duration) <-chan time.Time
+	afterMutex       sync.RWMutex
+	afterArgsForCall []struct {
+		arg1 time.Duration
+	}
+	afterReturns struct {
+		result1 <-chan time.Time
+	}
+}
+
+func (fake *FakeClock) CurrentTime() time.Time {
+	fake.currentTimeMutex.Lock()
+	fake.currentTimeArgsForCall = append(fake.currentTimeArgsForCall, struct{}{})
+	fake.currentTimeMutex.Unlock()
+	if fake.CurrentTimeStub != nil {
+		return fake.CurrentTimeStub()
+	} else {
+		return fake.currentTimeReturns.result1
+	}
+}
+
+func (fake *FakeClock) CurrentTimeCallCount() int {
+	fake.currentTimeMutex.RLock()
+	defer fake.currentTimeMutex.RUnlock()
+	return len(fake.currentTimeArgsForCall)
+}
+
+func (fake *FakeClock) CurrentTimeReturns(result1 time.Time) {
+	fake.CurrentTimeStub = nil
+	fake.currentTimeReturns = struct {
+		result1 time.Time
+	}{result1}
+}
+
+func (fake *FakeClock) After(arg1 time.Duration) <-chan time.Time {
+	fake.afterMutex.Lock()
+	fake.afterArgsForCall = append(fake.afterArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	fake.afterMutex.Unlock()
+	if fake.AfterStub != nil {
+		return fake.AfterStub(arg1)
+	} else {
+		return fake.afterReturns.result1
+	}
+}
+
+func (fake *FakeClock) AfterCallCount() int {
+	fake.afterMutex.RLock()
+	defer fake.afterMutex.RUnlock()
+	return len(fake.afterArgsForCall)
+}
+
+func (fake *FakeClock) AfterArgsForCall(i int) time.Duration {
+	fake.afterMutex.RLock()
+	defer fake.afterMutex.RUnlock()
+	return fake.afterArgsForCall[i].arg1
+}
+
+func (fake *FakeClock) AfterReturns(result1 <-chan time.Time) {
+	fake.AfterStub = nil
+	fake.afterReturns = struct {
+		result1 <-chan time.Time
+	}{result1}
+}
+
+var _ resource.Clock = new(FakeClock)
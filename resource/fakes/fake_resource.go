@@ -21,6 +21,16 @@ type FakeResource struct {
 		result3 turbine.Config
 		result4 error
 	}
+	FetchConfigStub        func(turbine.Input) (turbine.Input, turbine.Config, error)
+	fetchConfigMutex       sync.RWMutex
+	fetchConfigArgsForCall []struct {
+		arg1 turbine.Input
+	}
+	fetchConfigReturns struct {
+		result1 turbine.Input
+		result2 turbine.Config
+		result3 error
+	}
 	OutStub        func(io.Reader, turbine.Output) (turbine.Output, error)
 	outMutex       sync.RWMutex
 	outArgsForCall []struct {
@@ -77,6 +87,40 @@ func (fake *FakeResource) InReturns(result1 io.Reader, result2 turbine.Input, re
 	}{result1, result2, result3, result4}
 }
 
+func (fake *FakeResource) FetchConfig(arg1 turbine.Input) (turbine.Input, turbine.Config, error) {
+	fake.fetchConfigMutex.Lock()
+	fake.fetchConfigArgsForCall = append(fake.fetchConfigArgsForCall, struct {
+		arg1 turbine.Input
+	}{arg1})
+	fake.fetchConfigMutex.Unlock()
+	if fake.FetchConfigStub != nil {
+		return fake.FetchConfigStub(arg1)
+	} else {
+		return fake.fetchConfigReturns.result1, fake.fetchConfigReturns.result2, fake.fetchConfigReturns.result3
+	}
+}
+
+func (fake *FakeResource) FetchConfigCallCount() int {
+	fake.fetchConfigMutex.RLock()
+	defer fake.fetchConfigMutex.RUnlock()
+	return len(fake.fetchConfigArgsForCall)
+}
+
+func (fake *FakeResource) FetchConfigArgsForCall(i int) turbine.Input {
+	fake.fetchConfigMutex.RLock()
+	defer fake.fetchConfigMutex.RUnlock()
+	return fake.fetchConfigArgsForCall[i].arg1
+}
+
+func (fake *FakeResource) FetchConfigReturns(result1 turbine.Input, result2 turbine.Config, result3 error) {
+	fake.FetchConfigStub = nil
+	fake.fetchConfigReturns = struct {
+		result1 turbine.Input
+		result2 turbine.Config
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeResource) Out(arg1 io.Reader, arg2 turbine.Output) (turbine.Output, error) {
 	fake.outMutex.Lock()
 	fake.outArgsForCall = append(fake.outArgsForCall, struct {
@@ -5,21 +5,34 @@ import (
 	"io"
 	"sync"
 
+	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/resource"
 )
 
 type FakeTracker struct {
-	InitStub        func(typ string, logs io.Writer, abort <-chan struct{}) (resource.Resource, error)
+	InitStub        func(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (resource.Resource, error)
 	initMutex       sync.RWMutex
 	initArgsForCall []struct {
-		typ   string
-		logs  io.Writer
-		abort <-chan struct{}
+		typ      string
+		logs     io.Writer
+		progress io.Writer
+		abort    <-chan struct{}
 	}
 	initReturns struct {
 		result1 resource.Resource
 		result2 error
 	}
+	InitForCheckStub        func(typ string, source turbine.Source, abort <-chan struct{}) (resource.Resource, error)
+	initForCheckMutex       sync.RWMutex
+	initForCheckArgsForCall []struct {
+		typ    string
+		source turbine.Source
+		abort  <-chan struct{}
+	}
+	initForCheckReturns struct {
+		result1 resource.Resource
+		result2 error
+	}
 	ReleaseStub        func(resource.Resource) error
 	releaseMutex       sync.RWMutex
 	releaseArgsForCall []struct {
@@ -30,16 +43,17 @@ type FakeTracker struct {
 	}
 }
 
-func (fake *FakeTracker) Init(typ string, logs io.Writer, abort <-chan struct{}) (resource.Resource, error) {
+func (fake *FakeTracker) Init(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (resource.Resource, error) {
 	fake.initMutex.Lock()
 	fake.initArgsForCall = append(fake.initArgsForCall, struct {
-		typ   string
-		logs  io.Writer
-		abort <-chan struct{}
-	}{typ, logs, abort})
+		typ      string
+		logs     io.Writer
+		progress io.Writer
+		abort    <-chan struct{}
+	}{typ, logs, progress, abort})
 	fake.initMutex.Unlock()
 	if fake.InitStub != nil {
-		return fake.InitStub(typ, logs, abort)
+		return fake.InitStub(typ, logs, progress, abort)
 	} else {
 		return fake.initReturns.result1, fake.initReturns.result2
 	}
@@ -51,10 +65,10 @@ func (fake *FakeTracker) InitCallCount() int {
 	return len(fake.initArgsForCall)
 }
 
-func (fake *FakeTracker) InitArgsForCall(i int) (string, io.Writer, <-chan struct{}) {
+func (fake *FakeTracker) InitArgsForCall(i int) (string, io.Writer, io.Writer, <-chan struct{}) {
 	fake.initMutex.RLock()
 	defer fake.initMutex.RUnlock()
-	return fake.initArgsForCall[i].typ, fake.initArgsForCall[i].logs, fake.initArgsForCall[i].abort
+	return fake.initArgsForCall[i].typ, fake.initArgsForCall[i].logs, fake.initArgsForCall[i].progress, fake.initArgsForCall[i].abort
 }
 
 func (fake *FakeTracker) InitReturns(result1 resource.Resource, result2 error) {
@@ -65,6 +79,41 @@ func (fake *FakeTracker) InitReturns(result1 resource.Resource, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *FakeTracker) InitForCheck(typ string, source turbine.Source, abort <-chan struct{}) (resource.Resource, error) {
+	fake.initForCheckMutex.Lock()
+	fake.initForCheckArgsForCall = append(fake.initForCheckArgsForCall, struct {
+		typ    string
+		source turbine.Source
+		abort  <-chan struct{}
+	}{typ, source, abort})
+	fake.initForCheckMutex.Unlock()
+	if fake.InitForCheckStub != nil {
+		return fake.InitForCheckStub(typ, source, abort)
+	} else {
+		return fake.initForCheckReturns.result1, fake.initForCheckReturns.result2
+	}
+}
+
+func (fake *FakeTracker) InitForCheckCallCount() int {
+	fake.initForCheckMutex.RLock()
+	defer fake.initForCheckMutex.RUnlock()
+	return len(fake.initForCheckArgsForCall)
+}
+
+func (fake *FakeTracker) InitForCheckArgsForCall(i int) (string, turbine.Source, <-chan struct{}) {
+	fake.initForCheckMutex.RLock()
+	defer fake.initForCheckMutex.RUnlock()
+	return fake.initForCheckArgsForCall[i].typ, fake.initForCheckArgsForCall[i].source, fake.initForCheckArgsForCall[i].abort
+}
+
+func (fake *FakeTracker) InitForCheckReturns(result1 resource.Resource, result2 error) {
+	fake.InitForCheckStub = nil
+	fake.initForCheckReturns = struct {
+		result1 resource.Resource
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTracker) Release(arg1 resource.Resource) error {
 	fake.releaseMutex.Lock()
 	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
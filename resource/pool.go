@@ -0,0 +1,297 @@
+package resource
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/config"
+	"github.com/concourse/turbine/runtime"
+)
+
+// how often the pool expires idle containers and warms up new ones
+var PoolMaintenanceInterval = 10 * time.Second
+
+// ContainerPool keeps idle resource containers around so that checks, and
+// fetching or performing with a fresh container, don't have to wait for one
+// to be created. Fresh containers are kept at MinIdle, and used ones, which
+// only checks of the same source can reuse, are kept up to MaxIdle. It is an
+// ifrit.Runner that maintains the pool until signalled, at which point all
+// idle containers are destroyed.
+type ContainerPool struct {
+	logger        lager.Logger
+	resourceTypes config.ResourceTypes
+	runtime       runtime.Runtime
+	config        config.Pool
+	clock         Clock
+
+	idle   map[string][]pooledContainer
+	stats  PoolStats
+	closed bool
+	lock   *sync.Mutex
+}
+
+type pooledContainer struct {
+	container runtime.Container
+
+	// whether any scripts have run in it
+	used bool
+
+	// the source its checks were run against, if used
+	source string
+
+	idleSince time.Time
+}
+
+type PoolStats struct {
+	// containers handed out from the pool
+	Hits int64 `json:"hits"`
+
+	// containers that had to be created when asked for
+	Misses int64 `json:"misses"`
+
+	// containers currently idle in the pool, across all types
+	Idle int `json:"idle"`
+}
+
+func NewContainerPool(
+	logger lager.Logger,
+	resourceTypes config.ResourceTypes,
+	containerRuntime runtime.Runtime,
+	poolConfig config.Pool,
+	clock Clock,
+) *ContainerPool {
+	return &ContainerPool{
+		logger:        logger,
+		resourceTypes: resourceTypes,
+		runtime:       containerRuntime,
+		config:        poolConfig,
+		clock:         clock,
+
+		idle: make(map[string][]pooledContainer),
+		lock: new(sync.Mutex),
+	}
+}
+
+func (pool *ContainerPool) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	pool.maintain()
+
+	for {
+		select {
+		case <-pool.clock.After(PoolMaintenanceInterval):
+			pool.maintain()
+
+		case <-signals:
+			pool.close()
+			return nil
+		}
+	}
+}
+
+// Take hands out an idle container of the given resource type. If fresh is
+// true, only containers that have never run a script are considered;
+// otherwise, used containers are only handed out for the source they were
+// used with.
+func (pool *ContainerPool) Take(typ string, source turbine.Source, fresh bool) (runtime.Container, bool) {
+	key, keyed := sourceKey(source)
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	idle := pool.idle[typ]
+
+	// prefer used containers so that fresh ones are left for those who need
+	// them
+	chosen := -1
+	for i, pooled := range idle {
+		if pooled.used && !fresh && keyed && pooled.source == key {
+			chosen = i
+			break
+		}
+
+		if !pooled.used && chosen == -1 {
+			chosen = i
+		}
+	}
+
+	if chosen == -1 {
+		pool.stats.Misses++
+		return nil, false
+	}
+
+	container := idle[chosen].container
+	pool.idle[typ] = append(idle[:chosen], idle[chosen+1:]...)
+
+	pool.stats.Hits++
+
+	return container, true
+}
+
+// Put returns a container used with the given source to the pool. Containers
+// that cannot be reused, or that would exceed the pool's capacity, are
+// destroyed.
+func (pool *ContainerPool) Put(typ string, source turbine.Source, container runtime.Container, reusable bool) error {
+	key, keyed := sourceKey(source)
+
+	pool.lock.Lock()
+
+	if !reusable || !keyed || pool.closed || pool.count(typ, true) >= pool.config.MaxIdle {
+		pool.lock.Unlock()
+		return pool.runtime.Destroy(container.Handle())
+	}
+
+	pool.idle[typ] = append(pool.idle[typ], pooledContainer{
+		container: container,
+		used:      true,
+		source:    key,
+		idleSince: pool.clock.CurrentTime(),
+	})
+
+	pool.lock.Unlock()
+
+	return nil
+}
+
+func (pool *ContainerPool) Stats() PoolStats {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	stats := pool.stats
+	for _, idle := range pool.idle {
+		stats.Idle += len(idle)
+	}
+
+	return stats
+}
+
+// maintain destroys containers that have been idle for too long and creates
+// fresh ones to keep each type at its minimum.
+func (pool *ContainerPool) maintain() {
+	for _, resourceType := range pool.resourceTypes {
+		for _, expired := range pool.expire(resourceType.Name) {
+			err := pool.runtime.Destroy(expired.Handle())
+			if err != nil {
+				pool.logger.Error("failed-to-destroy-expired-container", err, lager.Data{
+					"type": resourceType.Name,
+				})
+			}
+		}
+
+		for i := pool.missing(resourceType.Name); i > 0; i-- {
+			container, err := createResourceContainer(pool.runtime, resourceType)
+			if err != nil {
+				pool.logger.Error("failed-to-warm-container", err, lager.Data{
+					"type": resourceType.Name,
+				})
+
+				break
+			}
+
+			pool.lock.Lock()
+
+			if pool.closed {
+				pool.lock.Unlock()
+				pool.runtime.Destroy(container.Handle())
+				return
+			}
+
+			pool.idle[resourceType.Name] = append(pool.idle[resourceType.Name], pooledContainer{
+				container: container,
+				idleSince: pool.clock.CurrentTime(),
+			})
+
+			pool.lock.Unlock()
+		}
+	}
+}
+
+func (pool *ContainerPool) expire(typ string) []runtime.Container {
+	if pool.config.IdleTTL == 0 {
+		return nil
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	now := pool.clock.CurrentTime()
+
+	expired := []runtime.Container{}
+	remaining := []pooledContainer{}
+
+	for _, pooled := range pool.idle[typ] {
+		if now.Sub(pooled.idleSince) >= pool.config.IdleTTL {
+			expired = append(expired, pooled.container)
+		} else {
+			remaining = append(remaining, pooled)
+		}
+	}
+
+	pool.idle[typ] = remaining
+
+	return expired
+}
+
+// missing returns how many fresh containers are needed to reach MinIdle;
+// used ones don't count, as they can't be handed out to everyone
+func (pool *ContainerPool) missing(typ string) int {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	return pool.config.MinIdle - pool.count(typ, false)
+}
+
+// count returns how many idle containers of the type are used, or fresh; the
+// lock must be held
+func (pool *ContainerPool) count(typ string, used bool) int {
+	count := 0
+	for _, pooled := range pool.idle[typ] {
+		if pooled.used == used {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (pool *ContainerPool) close() {
+	pool.lock.Lock()
+	idle := pool.idle
+	pool.idle = make(map[string][]pooledContainer)
+	pool.closed = true
+	pool.lock.Unlock()
+
+	for typ, containers := range idle {
+		for _, pooled := range containers {
+			err := pool.runtime.Destroy(pooled.container.Handle())
+			if err != nil {
+				pool.logger.Error("failed-to-destroy-idle-container", err, lager.Data{
+					"type": typ,
+				})
+			}
+		}
+	}
+}
+
+// sourceKey identifies a source; encoding maps as JSON sorts their keys, at
+// any depth, so equal sources yield equal keys
+func sourceKey(source turbine.Source) (string, bool) {
+	key, err := json.Marshal(source)
+	if err != nil {
+		return "", false
+	}
+
+	return string(key), true
+}
+
+func createResourceContainer(containerRuntime runtime.Runtime, resourceType config.ResourceType) (runtime.Container, error) {
+	return containerRuntime.Create(runtime.ContainerSpec{
+		RootFSPath: resourceType.Image,
+		Privileged: true,
+	})
+}
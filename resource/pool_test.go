@@ -0,0 +1,263 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+
+	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/config"
+	. "github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/resource/fakes"
+	"github.com/concourse/turbine/runtime"
+	rtfakes "github.com/concourse/turbine/runtime/fakes"
+)
+
+var _ = Describe("ContainerPool", func() {
+	var (
+		fakeRuntime *rtfakes.FakeRuntime
+		poolConfig  config.Pool
+		clock       *fakes.FakeClock
+		ticks       chan time.Time
+		now         time.Time
+		setNow      func(time.Time)
+
+		created   []string
+		createdL  *sync.Mutex
+		destroyed func() []string
+
+		pool *ContainerPool
+	)
+
+	BeforeEach(func() {
+		fakeRuntime = new(rtfakes.FakeRuntime)
+
+		created = nil
+		createdL = new(sync.Mutex)
+
+		fakeRuntime.CreateStub = func(spec runtime.ContainerSpec) (runtime.Container, error) {
+			createdL.Lock()
+			defer createdL.Unlock()
+
+			handle := fmt.Sprintf("%s-%d", spec.RootFSPath, len(created)+1)
+			created = append(created, handle)
+
+			container := new(rtfakes.FakeContainer)
+			container.HandleReturns(handle)
+
+			return container, nil
+		}
+
+		destroyed = func() []string {
+			handles := []string{}
+			for i := 0; i < fakeRuntime.DestroyCallCount(); i++ {
+				handles = append(handles, fakeRuntime.DestroyArgsForCall(i))
+			}
+
+			return handles
+		}
+
+		poolConfig = config.Pool{MaxIdle: 2}
+
+		ticks = make(chan time.Time)
+		now = time.Unix(123, 0)
+
+		// the pool reads the time while the test changes it
+		currentTime := now
+		currentTimeL := new(sync.Mutex)
+
+		setNow = func(t time.Time) {
+			currentTimeL.Lock()
+			currentTime = t
+			currentTimeL.Unlock()
+		}
+
+		clock = new(fakes.FakeClock)
+		clock.AfterReturns(ticks)
+		clock.CurrentTimeStub = func() time.Time {
+			currentTimeL.Lock()
+			defer currentTimeL.Unlock()
+			return currentTime
+		}
+	})
+
+	JustBeforeEach(func() {
+		pool = NewContainerPool(
+			lagertest.NewTestLogger("test"),
+			config.ResourceTypes{
+				{Name: "type1", Image: "image1"},
+				{Name: "type2", Image: "image2"},
+			},
+			fakeRuntime,
+			poolConfig,
+			clock,
+		)
+	})
+
+	source := turbine.Source{"uri": "example.com"}
+
+	newContainer := func(handle string) runtime.Container {
+		container := new(rtfakes.FakeContainer)
+		container.HandleReturns(handle)
+		return container
+	}
+
+	Describe("taking and putting containers", func() {
+		It("misses when the pool is empty", func() {
+			_, found := pool.Take("type1", source, false)
+			Ω(found).Should(BeFalse())
+
+			Ω(pool.Stats()).Should(Equal(PoolStats{Misses: 1}))
+		})
+
+		It("hands out reusable containers that were put back", func() {
+			container := newContainer("some-handle")
+
+			err := pool.Put("type1", source, container, true)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(pool.Stats()).Should(Equal(PoolStats{Idle: 1}))
+
+			taken, found := pool.Take("type1", source, false)
+			Ω(found).Should(BeTrue())
+			Ω(taken).Should(Equal(container))
+
+			Ω(pool.Stats()).Should(Equal(PoolStats{Hits: 1}))
+		})
+
+		It("does not hand out used containers to those who need a fresh one", func() {
+			err := pool.Put("type1", source, newContainer("some-handle"), true)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, found := pool.Take("type1", nil, true)
+			Ω(found).Should(BeFalse())
+		})
+
+		It("does not hand out containers used with a different source", func() {
+			err := pool.Put("type1", source, newContainer("some-handle"), true)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, found := pool.Take("type1", turbine.Source{"uri": "other.example.com"}, false)
+			Ω(found).Should(BeFalse())
+		})
+
+		It("keeps containers of each type separate", func() {
+			err := pool.Put("type1", source, newContainer("some-handle"), true)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, found := pool.Take("type2", source, false)
+			Ω(found).Should(BeFalse())
+		})
+
+		It("destroys containers that cannot be reused", func() {
+			err := pool.Put("type1", source, newContainer("some-handle"), false)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(destroyed()).Should(Equal([]string{"some-handle"}))
+			Ω(pool.Stats().Idle).Should(BeZero())
+		})
+
+		It("destroys containers beyond the maximum idle count", func() {
+			for i := 1; i <= 3; i++ {
+				err := pool.Put("type1", source, newContainer(fmt.Sprintf("handle-%d", i)), true)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+
+			Ω(destroyed()).Should(Equal([]string{"handle-3"}))
+			Ω(pool.Stats().Idle).Should(Equal(2))
+		})
+	})
+
+	Describe("running", func() {
+		var process ifrit.Process
+
+		BeforeEach(func() {
+			poolConfig = config.Pool{
+				MinIdle: 1,
+				MaxIdle: 2,
+			}
+		})
+
+		JustBeforeEach(func() {
+			process = ifrit.Invoke(pool)
+		})
+
+		AfterEach(func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("warms up fresh containers for each type", func() {
+			Eventually(func() int { return pool.Stats().Idle }).Should(Equal(2))
+
+			taken, found := pool.Take("type1", nil, true)
+			Ω(found).Should(BeTrue())
+			Ω(taken.Handle()).Should(HavePrefix("image1-"))
+
+			ticks <- now
+			Eventually(func() int { return pool.Stats().Idle }).Should(Equal(2))
+			Ω(fakeRuntime.CreateCallCount()).Should(Equal(3))
+
+			Ω(clock.AfterArgsForCall(0)).Should(Equal(PoolMaintenanceInterval))
+		})
+
+		It("keeps warming fresh containers once used ones fill the pool", func() {
+			Eventually(func() int { return pool.Stats().Idle }).Should(Equal(2))
+
+			_, found := pool.Take("type1", nil, true)
+			Ω(found).Should(BeTrue())
+
+			for i := 1; i <= 2; i++ {
+				source := turbine.Source{"uri": fmt.Sprintf("example-%d.com", i)}
+
+				err := pool.Put("type1", source, newContainer(fmt.Sprintf("used-%d", i)), true)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+
+			ticks <- now
+			Eventually(func() int { return pool.Stats().Idle }).Should(Equal(4))
+
+			taken, found := pool.Take("type1", nil, true)
+			Ω(found).Should(BeTrue())
+			Ω(taken.Handle()).Should(HavePrefix("image1-"))
+		})
+
+		It("destroys idle containers when signalled", func() {
+			Eventually(func() int { return pool.Stats().Idle }).Should(Equal(2))
+
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+
+			Ω(destroyed()).Should(ConsistOf(created))
+			Ω(pool.Stats().Idle).Should(BeZero())
+		})
+
+		Context("with an idle TTL", func() {
+			BeforeEach(func() {
+				poolConfig.IdleTTL = time.Minute
+			})
+
+			It("replaces containers that have been idle for too long", func() {
+				Eventually(func() int { return pool.Stats().Idle }).Should(Equal(2))
+
+				setNow(now.Add(time.Minute - time.Second))
+				ticks <- now
+				ticks <- now
+				Ω(fakeRuntime.DestroyCallCount()).Should(BeZero())
+
+				setNow(now.Add(time.Minute))
+				ticks <- now
+				Eventually(fakeRuntime.DestroyCallCount).Should(Equal(2))
+				Eventually(func() int { return pool.Stats().Idle }).Should(Equal(2))
+
+				Ω(fakeRuntime.CreateCallCount()).Should(Equal(4))
+			})
+		})
+	})
+})
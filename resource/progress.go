@@ -0,0 +1,145 @@
+package resource
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+// resource scripts may report progress by appending newline-delimited JSON
+// records to the file named by this environment variable
+const ProgressFileEnv = "TURBINE_PROGRESS_FILE"
+
+// how often a running script's progress file is checked for new records
+var ProgressPollInterval = time.Second
+
+// how much of a progress file is read on each poll; records longer than this
+// are dropped
+var ProgressReadLimit = 64 * 1024
+
+type progressTail struct {
+	container runtime.Container
+	path      string
+	dst       io.Writer
+
+	offset int64
+
+	// whether the record at offset is too long, and is being dropped
+	skipping bool
+}
+
+// progressTail allocates a fresh progress file for the next script, or
+// returns nil if nobody is listening for progress.
+func (resource *resource) progressTail() *progressTail {
+	if resource.progress == nil {
+		return nil
+	}
+
+	resource.scripts++
+
+	return &progressTail{
+		container: resource.container,
+		path:      fmt.Sprintf("/tmp/resource-progress-%d", resource.scripts),
+		dst:       resource.progress,
+	}
+}
+
+// follow polls the progress file until the returned function is called,
+// which polls until it has caught up to pick up any final records.
+func (tail *progressTail) follow() func() {
+	if tail == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(ProgressPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				tail.poll()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+
+		for tail.poll() {
+		}
+	}
+}
+
+// poll forwards any complete records written since the last poll, reading at
+// most ProgressReadLimit bytes, and returns whether there may be more to
+// read. The file may not exist yet, in which case there is nothing to
+// forward.
+func (tail *progressTail) poll() bool {
+	stream, err := tail.container.StreamOut(tail.path)
+	if err != nil {
+		return false
+	}
+
+	defer stream.Close()
+
+	reader := tar.NewReader(stream)
+
+	_, err = reader.Next()
+	if err != nil {
+		return false
+	}
+
+	_, err = io.CopyN(ioutil.Discard, reader, tail.offset)
+	if err != nil {
+		return false
+	}
+
+	unread, err := ioutil.ReadAll(io.LimitReader(reader, int64(ProgressReadLimit)))
+	if err != nil {
+		return false
+	}
+
+	full := len(unread) == ProgressReadLimit
+
+	if tail.skipping {
+		end := bytes.Index(unread, []byte("\n")) + 1
+		if end == 0 {
+			tail.offset += int64(len(unread))
+			return full
+		}
+
+		tail.skipping = false
+		tail.offset += int64(end)
+		unread = unread[end:]
+	}
+
+	complete := bytes.LastIndex(unread, []byte("\n")) + 1
+	if complete == 0 {
+		if len(unread) == ProgressReadLimit {
+			// no record this long will ever be forwarded
+			tail.skipping = true
+			tail.offset += int64(len(unread))
+		}
+
+		return full
+	}
+
+	tail.dst.Write(unread[:complete])
+	tail.offset += int64(complete)
+
+	return full
+}
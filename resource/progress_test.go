@@ -0,0 +1,144 @@
+package resource_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	garden "github.com/cloudfoundry-incubator/garden/api"
+	gfakes "github.com/cloudfoundry-incubator/garden/api/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/concourse/turbine"
+	. "github.com/concourse/turbine/resource"
+)
+
+var _ = Describe("Resource Progress", func() {
+	var (
+		progress *gbytes.Buffer
+
+		progressFiles map[string]string
+
+		runSpecs []garden.ProcessSpec
+	)
+
+	BeforeEach(func() {
+		progress = gbytes.NewBuffer()
+		resource = NewResource(container, logs, progress, abort, 0)
+
+		progressFiles = map[string]string{}
+		runSpecs = nil
+
+		gardenClient.Connection.RunStub = func(handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+			runSpecs = append(runSpecs, spec)
+
+			io.Stdout.Write([]byte("[]"))
+
+			process := new(gfakes.FakeProcess)
+			process.WaitReturns(0, nil)
+
+			return process, nil
+		}
+
+		gardenClient.Connection.StreamOutStub = func(handle string, src string) (io.ReadCloser, error) {
+			contents, found := progressFiles[src]
+			if !found {
+				return nil, errors.New("no such file")
+			}
+
+			buf := new(bytes.Buffer)
+
+			tarWriter := tar.NewWriter(buf)
+
+			tarWriter.WriteHeader(&tar.Header{
+				Name: "./doesnt-matter",
+				Mode: 0644,
+				Size: int64(len(contents)),
+			})
+
+			tarWriter.Write([]byte(contents))
+			tarWriter.Close()
+
+			return ioutil.NopCloser(buf), nil
+		}
+	})
+
+	It("gives each script its own progress file", func() {
+		_, err := resource.Check(turbine.Input{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = resource.Check(turbine.Input{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(runSpecs).Should(HaveLen(2))
+		Ω(runSpecs[0].Env).Should(Equal([]string{"TURBINE_PROGRESS_FILE=/tmp/resource-progress-1"}))
+		Ω(runSpecs[1].Env).Should(Equal([]string{"TURBINE_PROGRESS_FILE=/tmp/resource-progress-2"}))
+	})
+
+	It("forwards complete progress records written by the script", func() {
+		progressFiles["/tmp/resource-progress-1"] = `{"current":1}` + "\n" + `{"curr`
+
+		_, err := resource.Check(turbine.Input{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(progress.Contents()).Should(Equal([]byte(`{"current":1}` + "\n")))
+	})
+
+	Context("when there is more progress than is read per poll", func() {
+		var originalLimit int
+
+		BeforeEach(func() {
+			originalLimit = ProgressReadLimit
+			ProgressReadLimit = 16
+		})
+
+		AfterEach(func() {
+			ProgressReadLimit = originalLimit
+		})
+
+		It("forwards all of it over several reads", func() {
+			progressFiles["/tmp/resource-progress-1"] = `{"current":1}` + "\n" + `{"current":2}` + "\n" + `{"current":3}` + "\n"
+
+			_, err := resource.Check(turbine.Input{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(progress.Contents())).Should(Equal(`{"current":1}` + "\n" + `{"current":2}` + "\n" + `{"current":3}` + "\n"))
+		})
+
+		It("drops records too long to be read at once", func() {
+			progressFiles["/tmp/resource-progress-1"] = `{"current":1,"total":1000000}` + "\n" + `{"current":2}` + "\n"
+
+			_, err := resource.Check(turbine.Input{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(progress.Contents())).Should(Equal(`{"current":2}` + "\n"))
+		})
+	})
+
+	Context("when the script writes no progress", func() {
+		It("succeeds without forwarding anything", func() {
+			_, err := resource.Check(turbine.Input{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(progress.Contents()).Should(BeEmpty())
+		})
+	})
+
+	Context("when nothing is listening for progress", func() {
+		BeforeEach(func() {
+			resource = NewResource(container, logs, nil, abort, 0)
+		})
+
+		It("does not give the script a progress file", func() {
+			_, err := resource.Check(turbine.Input{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(runSpecs[0].Env).Should(BeEmpty())
+			Ω(gardenClient.Connection.StreamOutCallCount()).Should(Equal(0))
+		})
+	})
+})
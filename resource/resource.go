@@ -2,13 +2,19 @@ package resource
 
 import (
 	"io"
+	"time"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/concourse/turbine"
+	"github.com/concourse/turbine/runtime"
 )
 
 type Resource interface {
 	In(turbine.Input) (io.Reader, turbine.Input, turbine.Config, error)
+
+	// like In, but only returns the input's build config rather than
+	// streaming out all of its bits
+	FetchConfig(turbine.Input) (turbine.Input, turbine.Config, error)
+
 	Out(io.Reader, turbine.Output) (turbine.Output, error)
 	Check(turbine.Input) ([]turbine.Version, error)
 }
@@ -16,19 +22,49 @@ type Resource interface {
 const ResourcesDir = "/tmp/build/src"
 
 type resource struct {
-	container garden.Container
+	container runtime.Container
 	logs      io.Writer
+	progress  io.Writer
 	abort     <-chan struct{}
+	timeout   time.Duration
+
+	// number of scripts run so far, so that each gets its own progress file
+	scripts int
+
+	// whether any script failed, timed out, or was aborted, leaving the
+	// container in an unknown state
+	failed bool
 }
 
+// NewResource returns a resource whose scripts run in the given container.
+// If progress is nil, scripts are not told where to report progress.
 func NewResource(
-	container garden.Container,
+	container runtime.Container,
 	logs io.Writer,
+	progress io.Writer,
 	abort <-chan struct{},
+	timeout time.Duration,
 ) Resource {
 	return &resource{
 		container: container,
 		logs:      logs,
+		progress:  progress,
 		abort:     abort,
+		timeout:   timeout,
+	}
+}
+
+// scriptFailed reports whether the container may have been left in an
+// unknown state by a script
+func (resource *resource) scriptFailed() bool {
+	return resource.failed
+}
+
+// scriptTimeout is the requested timeout if given, or the resource's default
+func (resource *resource) scriptTimeout(requested turbine.Duration) time.Duration {
+	if requested > 0 {
+		return time.Duration(requested)
 	}
+
+	return resource.timeout
 }
@@ -13,6 +13,7 @@ func (resource *resource) Check(input turbine.Input) ([]turbine.Version, error)
 	err := resource.runScript(
 		"/opt/resource/check",
 		nil,
+		resource.scriptTimeout(input.Timeout),
 		versionAndSource{input.Version, input.Source},
 		&versions,
 	)
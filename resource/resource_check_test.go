@@ -3,10 +3,13 @@ package resource_test
 import (
 	"errors"
 	"io/ioutil"
+	"strings"
+	"time"
 
 	garden "github.com/cloudfoundry-incubator/garden/api"
 	gfakes "github.com/cloudfoundry-incubator/garden/api/fakes"
 	"github.com/concourse/turbine"
+	. "github.com/concourse/turbine/resource"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -125,13 +128,124 @@ var _ = Describe("Resource Check", func() {
 		})
 	})
 
+	Context("when /opt/resource/check exits nonzero with a lot of output", func() {
+		BeforeEach(func() {
+			checkScriptStdout = strings.Repeat("o", 100*1024)
+			checkScriptStderr = strings.Repeat("e", 100*1024)
+			checkScriptExitStatus = 1
+		})
+
+		It("keeps only the start of each", func() {
+			scriptErr, ok := checkErr.(ErrResourceScriptFailed)
+			Ω(ok).Should(BeTrue())
+
+			Ω(scriptErr.Stdout).Should(Equal(strings.Repeat("o", 64*1024) + "\n... (36864 bytes truncated)"))
+			Ω(scriptErr.Stderr).Should(Equal(strings.Repeat("e", 64*1024) + "\n... (36864 bytes truncated)"))
+		})
+	})
+
 	Context("when the output of /opt/resource/check is malformed", func() {
 		BeforeEach(func() {
 			checkScriptStdout = "ß"
 		})
 
-		It("returns an error", func() {
+		It("returns an error showing the output", func() {
+			scriptErr, ok := checkErr.(ErrResourceScriptMalformedOutput)
+			Ω(ok).Should(BeTrue())
+
+			Ω(scriptErr.Path).Should(Equal("/opt/resource/check"))
+			Ω(scriptErr.Stdout).Should(Equal("ß"))
+			Ω(checkErr.Error()).Should(ContainSubstring("malformed output"))
+		})
+	})
+
+	Context("when the output of /opt/resource/check is long and malformed", func() {
+		BeforeEach(func() {
+			checkScriptStdout = strings.Repeat("x", 2048)
+		})
+
+		It("shows only the first bytes", func() {
+			scriptErr, ok := checkErr.(ErrResourceScriptMalformedOutput)
+			Ω(ok).Should(BeTrue())
+
+			Ω(scriptErr.Stdout).Should(Equal(strings.Repeat("x", 1024) + "..."))
+		})
+	})
+
+	Context("when /opt/resource/check outputs nothing", func() {
+		BeforeEach(func() {
+			checkScriptStdout = ""
+		})
+
+		It("returns an error saying so", func() {
 			Ω(checkErr).Should(HaveOccurred())
+			Ω(checkErr.Error()).Should(ContainSubstring("malformed output: no response"))
+		})
+	})
+
+	Context("when /opt/resource/check hangs", func() {
+		BeforeEach(func() {
+			checkScriptProcess.WaitStub = func() (int, error) {
+				select {}
+			}
+		})
+
+		Context("and the input has a timeout", func() {
+			BeforeEach(func() {
+				input.Timeout = turbine.Duration(10 * time.Millisecond)
+			})
+
+			It("returns ErrResourceScriptTimedOut", func() {
+				Ω(checkErr).Should(Equal(ErrResourceScriptTimedOut{
+					Path:    "/opt/resource/check",
+					Timeout: 10 * time.Millisecond,
+				}))
+			})
+
+			It("kills the container", func() {
+				Ω(gardenClient.Connection.StopCallCount()).Should(Equal(1))
+
+				handle, kill := gardenClient.Connection.StopArgsForCall(0)
+				Ω(handle).Should(Equal("some-handle"))
+				Ω(kill).Should(BeTrue())
+			})
+
+			Context("after writing part of a response", func() {
+				BeforeEach(func() {
+					checkScriptStdout = `[{"ref": "a"}, {"ref":`
+				})
+
+				It("returns no versions", func() {
+					Ω(checkErr).Should(BeAssignableToTypeOf(ErrResourceScriptTimedOut{}))
+					Ω(checkResult).Should(BeEmpty())
+				})
+			})
+		})
+
+		Context("and the resource has a default timeout", func() {
+			BeforeEach(func() {
+				resource = NewResource(container, logs, nil, abort, 10*time.Millisecond)
+			})
+
+			It("returns ErrResourceScriptTimedOut", func() {
+				Ω(checkErr).Should(Equal(ErrResourceScriptTimedOut{
+					Path:    "/opt/resource/check",
+					Timeout: 10 * time.Millisecond,
+				}))
+			})
+
+			Context("and the input has a longer timeout", func() {
+				BeforeEach(func() {
+					input.Timeout = turbine.Duration(20 * time.Millisecond)
+				})
+
+				It("uses the input's timeout", func() {
+					Ω(checkErr).Should(Equal(ErrResourceScriptTimedOut{
+						Path:    "/opt/resource/check",
+						Timeout: 20 * time.Millisecond,
+					}))
+				})
+			})
 		})
 	})
 })
@@ -29,32 +29,42 @@ type inResponse struct {
 }
 
 func (resource *resource) In(input turbine.Input) (io.Reader, turbine.Input, turbine.Config, error) {
+	input, buildConfig, err := resource.FetchConfig(input)
+	if err != nil {
+		return nil, turbine.Input{}, turbine.Config{}, err
+	}
+
+	outStream, err := resource.container.StreamOut(path.Join(ResourcesDir, input.Name) + "/")
+	if err != nil {
+		return nil, turbine.Input{}, turbine.Config{}, err
+	}
+
+	return outStream, input, buildConfig, nil
+}
+
+func (resource *resource) FetchConfig(input turbine.Input) (turbine.Input, turbine.Config, error) {
 	var resp inResponse
 
 	err := resource.runScript(
 		"/opt/resource/in",
 		[]string{path.Join(ResourcesDir, input.Name)},
+		resource.scriptTimeout(input.Timeout),
 		inRequest{input.Source, input.Params, input.Version},
 		&resp,
 	)
 	if err != nil {
-		return nil, turbine.Input{}, turbine.Config{}, err
+		return turbine.Input{}, turbine.Config{}, err
 	}
 
 	buildConfig, err := resource.extractConfig(input)
 	if err != nil {
-		return nil, turbine.Input{}, turbine.Config{}, err
+		return turbine.Input{}, turbine.Config{}, err
 	}
 
 	input.Version = resp.Version
 	input.Metadata = resp.Metadata
 
-	outStream, err := resource.container.StreamOut(path.Join(ResourcesDir, input.Name) + "/")
-	if err != nil {
-		return nil, turbine.Input{}, turbine.Config{}, err
-	}
-
-	return outStream, input, buildConfig, nil
+	return input, buildConfig, nil
 }
 
 func (resource *resource) extractConfig(input turbine.Input) (turbine.Config, error) {
@@ -80,9 +90,14 @@ func (resource *resource) extractConfig(input turbine.Input) (turbine.Config, er
 		return turbine.Config{}, err
 	}
 
-	var buildConfig turbine.Config
+	var configFile buildConfigFile
+
+	err = candiedyaml.NewDecoder(reader).Decode(&configFile)
+	if err != nil {
+		return turbine.Config{}, fmt.Errorf("invalid build config '%s': %s", input.ConfigPath, err)
+	}
 
-	err = candiedyaml.NewDecoder(reader).Decode(&buildConfig)
+	buildConfig, err := configFile.Config()
 	if err != nil {
 		return turbine.Config{}, fmt.Errorf("invalid build config '%s': %s", input.ConfigPath, err)
 	}
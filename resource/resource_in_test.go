@@ -180,6 +180,80 @@ var _ = Describe("Resource In", func() {
 				Ω(inConfig.Image).Should(Equal("some-reconfigured-image"))
 			})
 
+			Describe("fetching only the config", func() {
+				It("returns the config without streaming out the source", func() {
+					streamOuts := gardenClient.Connection.StreamOutCallCount()
+
+					fetchedInput, fetchedConfig, err := resource.FetchConfig(input)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(fetchedInput.Name).Should(Equal("some-name"))
+					Ω(fetchedConfig.Image).Should(Equal("some-reconfigured-image"))
+
+					Ω(gardenClient.Connection.StreamOutCallCount()).Should(Equal(streamOuts + 1))
+
+					_, src := gardenClient.Connection.StreamOutArgsForCall(streamOuts)
+					Ω(src).Should(Equal("/tmp/build/src/some-name/some/config/path.yml"))
+				})
+			})
+
+			Context("and it has params of various types", func() {
+				BeforeEach(func() {
+					gardenClient.Connection.StreamOutStub = func(handle string, src string) (io.ReadCloser, error) {
+						buf := new(bytes.Buffer)
+
+						if src == "/tmp/build/src/some-name/some/config/path.yml" {
+							tarWriter := tar.NewWriter(buf)
+
+							contents := []byte(`---
+params:
+  STRING: some-string
+  NUMBER: 42
+  BOOL: true
+  EMPTY:
+  LIST: [1, "two"]
+  MAP:
+    b: 2
+    a: {c: 3}
+secret_params:
+  TOKEN: s3cr3t
+  PIN: 1234
+`)
+
+							tarWriter.WriteHeader(&tar.Header{
+								Name: "./doesnt-matter",
+								Mode: 0644,
+								Size: int64(len(contents)),
+							})
+
+							tarWriter.Write(contents)
+						}
+
+						return ioutil.NopCloser(buf), nil
+					}
+				})
+
+				It("renders them as strings, with lists and maps as JSON", func() {
+					Ω(inErr).ShouldNot(HaveOccurred())
+
+					Ω(inConfig.Params).Should(Equal(map[string]string{
+						"STRING": "some-string",
+						"NUMBER": "42",
+						"BOOL":   "true",
+						"EMPTY":  "",
+						"LIST":   `[1,"two"]`,
+						"MAP":    `{"a":{"c":3},"b":2}`,
+					}))
+				})
+
+				It("renders secret params the same way", func() {
+					Ω(inConfig.SecretParams).Should(Equal(map[string]string{
+						"TOKEN": "s3cr3t",
+						"PIN":   "1234",
+					}))
+				})
+			})
+
 			Context("but the output is invalid", func() {
 				BeforeEach(func() {
 					gardenClient.Connection.StreamOutStub = func(handle string, src string) (io.ReadCloser, error) {
@@ -30,6 +30,7 @@ func (resource *resource) Out(sourceStream io.Reader, output turbine.Output) (tu
 	err = resource.runScript(
 		"/opt/resource/out",
 		[]string{ResourcesDir},
+		resource.scriptTimeout(output.Timeout),
 		outRequest{
 			Params:  output.Params,
 			Source:  output.Source,
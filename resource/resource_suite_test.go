@@ -3,13 +3,14 @@ package resource_test
 import (
 	"testing"
 
-	garden_api "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/cloudfoundry-incubator/garden/client/fake_api_client"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 
 	. "github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/runtime"
+	gardenruntime "github.com/concourse/turbine/runtime/garden"
 )
 
 var (
@@ -18,7 +19,8 @@ var (
 	logs  *gbytes.Buffer
 	abort chan struct{}
 
-	resource Resource
+	container runtime.Container
+	resource  Resource
 )
 
 var _ = BeforeEach(func() {
@@ -26,13 +28,14 @@ var _ = BeforeEach(func() {
 
 	gardenClient.Connection.CreateReturns("some-handle", nil)
 
-	container, err := gardenClient.Create(garden_api.ContainerSpec{})
+	var err error
+	container, err = gardenruntime.New(gardenClient).Create(runtime.ContainerSpec{})
 	Ω(err).ShouldNot(HaveOccurred())
 
 	logs = gbytes.NewBuffer()
 	abort = make(chan struct{})
 
-	resource = NewResource(container, logs, abort)
+	resource = NewResource(container, logs, nil, abort, 0)
 })
 
 func TestResource(t *testing.T) {
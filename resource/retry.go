@@ -0,0 +1,51 @@
+package resource
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/concourse/turbine"
+)
+
+// WithRetries calls attempt until it succeeds, its resource script fails for
+// the last time allowed by the policy, or it fails in some other way. Each
+// retry is announced on logs. The error from the final attempt is returned.
+func WithRetries(
+	policy *turbine.RetryPolicy,
+	logs io.Writer,
+	abort <-chan struct{},
+	attempt func() error,
+) error {
+	maxAttempts := policy.MaxAttempts()
+
+	for i := 1; ; i++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		scriptErr, retryable := err.(ErrResourceScriptFailed)
+		if !retryable || i == maxAttempts {
+			return err
+		}
+
+		backoff := policy.BackoffBefore(i + 1)
+
+		fmt.Fprintf(
+			logs,
+			"%s exited with status %d (attempt %d of %d); retrying in %s\n",
+			scriptErr.Path,
+			scriptErr.ExitStatus,
+			i,
+			maxAttempts,
+			backoff,
+		)
+
+		select {
+		case <-time.After(backoff):
+		case <-abort:
+			return ErrAborted
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package resource_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/concourse/turbine"
+	. "github.com/concourse/turbine/resource"
+)
+
+var _ = Describe("Retrying", func() {
+	var (
+		policy *turbine.RetryPolicy
+		logs   *gbytes.Buffer
+		abort  chan struct{}
+
+		attempts int
+		failures []error
+
+		retryErr error
+	)
+
+	scriptFailure := ErrResourceScriptFailed{
+		Path:       "/opt/resource/in",
+		Args:       []string{"/tmp/build/src/some-input"},
+		Stderr:     "network is down",
+		ExitStatus: 1,
+	}
+
+	BeforeEach(func() {
+		policy = &turbine.RetryPolicy{
+			Attempts: 3,
+			Backoff:  turbine.Duration(time.Millisecond),
+		}
+
+		logs = gbytes.NewBuffer()
+		abort = make(chan struct{})
+
+		attempts = 0
+		failures = nil
+	})
+
+	JustBeforeEach(func() {
+		retryErr = WithRetries(policy, logs, abort, func() error {
+			attempts++
+
+			if len(failures) == 0 {
+				return nil
+			}
+
+			err := failures[0]
+			failures = failures[1:]
+
+			return err
+		})
+	})
+
+	Context("when the script fails and then succeeds", func() {
+		BeforeEach(func() {
+			failures = []error{scriptFailure}
+		})
+
+		It("retries and succeeds", func() {
+			Ω(retryErr).ShouldNot(HaveOccurred())
+			Ω(attempts).Should(Equal(2))
+		})
+
+		It("logs the retry", func() {
+			Ω(logs).Should(gbytes.Say(`/opt/resource/in exited with status 1 \(attempt 1 of 3\); retrying in 1ms`))
+		})
+	})
+
+	Context("when the script fails every time", func() {
+		BeforeEach(func() {
+			lastFailure := scriptFailure
+			lastFailure.Stderr = "still down"
+
+			failures = []error{scriptFailure, scriptFailure, lastFailure}
+		})
+
+		It("gives up after the last attempt, returning its error", func() {
+			Ω(attempts).Should(Equal(3))
+			Ω(retryErr).Should(Equal(ErrResourceScriptFailed{
+				Path:       "/opt/resource/in",
+				Args:       []string{"/tmp/build/src/some-input"},
+				Stderr:     "still down",
+				ExitStatus: 1,
+			}))
+		})
+	})
+
+	Context("when there is no policy", func() {
+		BeforeEach(func() {
+			policy = nil
+			failures = []error{scriptFailure}
+		})
+
+		It("does not retry", func() {
+			Ω(attempts).Should(Equal(1))
+			Ω(retryErr).Should(Equal(scriptFailure))
+		})
+	})
+
+	Context("when the attempt fails in some other way", func() {
+		disaster := errors.New("oh no!")
+
+		BeforeEach(func() {
+			failures = []error{disaster}
+		})
+
+		It("does not retry", func() {
+			Ω(attempts).Should(Equal(1))
+			Ω(retryErr).Should(Equal(disaster))
+		})
+	})
+
+	Context("when aborted while backing off", func() {
+		BeforeEach(func() {
+			policy.Backoff = turbine.Duration(time.Hour)
+			failures = []error{scriptFailure}
+
+			close(abort)
+		})
+
+		It("returns ErrAborted", func() {
+			Ω(attempts).Should(Equal(1))
+			Ω(retryErr).Should(Equal(ErrAborted))
+		})
+	})
+})
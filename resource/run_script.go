@@ -6,12 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"time"
 
-	garden_api "github.com/cloudfoundry-incubator/garden/api"
+	"github.com/concourse/turbine/runtime"
 )
 
 var ErrAborted = errors.New("script aborted")
 
+// how much of a script's stdout and stderr to keep for error messages
+const scriptOutputLimit = 64 * 1024
+
+// how much of a script's stdout may be decoded as its response
+const maxResponseSize = 10 * 1024 * 1024
+
+// how much of a malformed response to show
+const malformedOutputPreview = 1024
+
 type ErrResourceScriptFailed struct {
 	Path       string
 	Args       []string
@@ -31,28 +42,101 @@ func (err ErrResourceScriptFailed) Error() string {
 	)
 }
 
-func (resource *resource) runScript(path string, args []string, input interface{}, output interface{}) error {
+type ErrResourceScriptTimedOut struct {
+	Path    string
+	Args    []string
+	Timeout time.Duration
+}
+
+func (err ErrResourceScriptTimedOut) Error() string {
+	return fmt.Sprintf(
+		"resource script '%s %v' timed out after %s",
+		err.Path,
+		err.Args,
+		err.Timeout,
+	)
+}
+
+type ErrResourceScriptMalformedOutput struct {
+	Path   string
+	Args   []string
+	Stdout string
+	Err    error
+}
+
+func (err ErrResourceScriptMalformedOutput) Error() string {
+	return fmt.Sprintf(
+		"resource script '%s %v' returned malformed output: %s\n\nstdout:\n\n%s",
+		err.Path,
+		err.Args,
+		err.Err,
+		err.Stdout,
+	)
+}
+
+// runScript runs the script, killing it if it outlives a non-zero timeout
+func (resource *resource) runScript(
+	path string,
+	args []string,
+	timeout time.Duration,
+	input interface{},
+	output interface{},
+) (err error) {
+	defer func() {
+		if err != nil {
+			resource.failed = true
+		}
+	}()
+
 	request, err := json.Marshal(input)
 	if err != nil {
 		return err
 	}
 
-	stdout := new(bytes.Buffer)
-	stderr := new(bytes.Buffer)
+	stdout := newBoundedBuffer(scriptOutputLimit)
+	stderr := newBoundedBuffer(scriptOutputLimit)
+
+	// decode the response as it arrives rather than buffering all of it
+	responseReader, responseWriter := io.Pipe()
 
-	process, err := resource.container.Run(garden_api.ProcessSpec{
+	var decodeErr error
+	decoded := make(chan struct{})
+	go func() {
+		defer close(decoded)
+		decodeErr = json.NewDecoder(io.LimitReader(responseReader, maxResponseSize)).Decode(output)
+		io.Copy(ioutil.Discard, responseReader)
+	}()
+
+	// the decoder must be done with output before returning, even if the
+	// script is abandoned
+	defer func() {
+		responseWriter.Close()
+		<-decoded
+	}()
+
+	spec := runtime.ProcessSpec{
 		Path:       path,
 		Args:       args,
 		Privileged: true,
-	}, garden_api.ProcessIO{
+	}
+
+	progress := resource.progressTail()
+	if progress != nil {
+		spec.Env = []string{ProgressFileEnv + "=" + progress.path}
+	}
+
+	process, err := resource.container.Run(spec, runtime.ProcessIO{
 		Stdin:  bytes.NewBuffer(request),
 		Stderr: io.MultiWriter(resource.logs, stderr),
-		Stdout: stdout,
+		Stdout: io.MultiWriter(responseWriter, stdout),
 	})
 	if err != nil {
 		return err
 	}
 
+	stopFollowing := progress.follow()
+	defer stopFollowing()
+
 	statusCh := make(chan int, 1)
 	errCh := make(chan error, 1)
 
@@ -65,8 +149,19 @@ func (resource *resource) runScript(path string, args []string, input interface{
 		}
 	}()
 
+	var timedOut <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		timedOut = timer.C
+	}
+
 	select {
 	case status := <-statusCh:
+		responseWriter.Close()
+		<-decoded
+
 		if status != 0 {
 			return ErrResourceScriptFailed{
 				Path:       path,
@@ -77,7 +172,11 @@ func (resource *resource) runScript(path string, args []string, input interface{
 			}
 		}
 
-		return json.Unmarshal(stdout.Bytes(), output)
+		if decodeErr != nil {
+			return malformedOutput(path, args, stdout, decodeErr)
+		}
+
+		return nil
 
 	case err := <-errCh:
 		return err
@@ -85,5 +184,33 @@ func (resource *resource) runScript(path string, args []string, input interface{
 	case <-resource.abort:
 		resource.container.Stop(false)
 		return ErrAborted
+
+	case <-timedOut:
+		resource.container.Stop(true)
+		return ErrResourceScriptTimedOut{
+			Path:    path,
+			Args:    args,
+			Timeout: timeout,
+		}
+	}
+}
+
+func malformedOutput(path string, args []string, stdout *boundedBuffer, err error) error {
+	if stdout.Total() > maxResponseSize {
+		err = fmt.Errorf("response exceeds %d bytes", maxResponseSize)
+	} else if err == io.EOF {
+		err = errors.New("no response")
+	}
+
+	preview := stdout.buf.String()
+	if len(preview) > malformedOutputPreview {
+		preview = preview[:malformedOutputPreview] + "..."
+	}
+
+	return ErrResourceScriptMalformedOutput{
+		Path:   path,
+		Args:   args,
+		Stdout: preview,
+		Err:    err,
 	}
 }
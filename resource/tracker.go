@@ -3,55 +3,106 @@ package resource
 import (
 	"errors"
 	"io"
+	"io/ioutil"
 	"sync"
 
-	garden_api "github.com/cloudfoundry-incubator/garden/api"
+	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/config"
+	"github.com/concourse/turbine/runtime"
 )
 
 type Tracker interface {
-	Init(typ string, logs io.Writer, abort <-chan struct{}) (Resource, error)
+	// Init returns a resource in a container that has never run a script,
+	// for fetching inputs and performing outputs.
+	Init(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (Resource, error)
+
+	// InitForCheck returns a resource for checking the given source, whose
+	// container may be shared with previous checks of the same source.
+	InitForCheck(typ string, source turbine.Source, abort <-chan struct{}) (Resource, error)
+
 	Release(Resource) error
 }
 
 type tracker struct {
 	resourceTypes config.ResourceTypes
-	gardenClient  garden_api.Client
+	runtime       runtime.Runtime
+	pool          *ContainerPool
 
-	containers  map[Resource]garden_api.Container
+	containers  map[Resource]trackedContainer
 	containersL *sync.Mutex
 }
 
+type trackedContainer struct {
+	container runtime.Container
+	typ       string
+	source    turbine.Source
+
+	// whether it may be returned to the pool for checking again
+	reusable bool
+}
+
 var ErrUnknownResourceType = errors.New("unknown resource type")
 
-func NewTracker(resourceTypes config.ResourceTypes, gardenClient garden_api.Client) Tracker {
+type ErrCreatingContainer struct {
+	Err error
+}
+
+func (err ErrCreatingContainer) Error() string {
+	return "failed to create container: " + err.Err.Error()
+}
+
+// NewTracker returns a tracker that takes containers from the pool if
+// given, and otherwise creates and destroys one for every resource.
+func NewTracker(resourceTypes config.ResourceTypes, containerRuntime runtime.Runtime, pool *ContainerPool) Tracker {
 	return &tracker{
 		resourceTypes: resourceTypes,
-		gardenClient:  gardenClient,
+		runtime:       containerRuntime,
+		pool:          pool,
 
-		containers:  make(map[Resource]garden_api.Container),
+		containers:  make(map[Resource]trackedContainer),
 		containersL: new(sync.Mutex),
 	}
 }
 
-func (tracker *tracker) Init(typ string, logs io.Writer, abort <-chan struct{}) (Resource, error) {
+func (tracker *tracker) Init(typ string, logs io.Writer, progress io.Writer, abort <-chan struct{}) (Resource, error) {
+	return tracker.init(typ, nil, logs, progress, abort, false)
+}
+
+func (tracker *tracker) InitForCheck(typ string, source turbine.Source, abort <-chan struct{}) (Resource, error) {
+	return tracker.init(typ, source, ioutil.Discard, nil, abort, true)
+}
+
+func (tracker *tracker) init(typ string, source turbine.Source, logs io.Writer, progress io.Writer, abort <-chan struct{}, check bool) (Resource, error) {
 	resourceType, found := tracker.resourceTypes.Lookup(typ)
 	if !found {
 		return nil, ErrUnknownResourceType
 	}
 
-	container, err := tracker.gardenClient.Create(garden_api.ContainerSpec{
-		RootFSPath: resourceType.Image,
-		Privileged: true,
-	})
-	if err != nil {
-		return nil, err
+	var container runtime.Container
+
+	pooled := false
+	if tracker.pool != nil {
+		container, pooled = tracker.pool.Take(typ, source, !check)
+	}
+
+	if !pooled {
+		var err error
+
+		container, err = createResourceContainer(tracker.runtime, resourceType)
+		if err != nil {
+			return nil, ErrCreatingContainer{err}
+		}
 	}
 
-	resource := NewResource(container, logs, abort)
+	resource := NewResource(container, logs, progress, abort, resourceType.Timeout)
 
 	tracker.containersL.Lock()
-	tracker.containers[resource] = container
+	tracker.containers[resource] = trackedContainer{
+		container: container,
+		typ:       typ,
+		source:    source,
+		reusable:  check,
+	}
 	tracker.containersL.Unlock()
 
 	return resource, nil
@@ -59,7 +110,7 @@ func (tracker *tracker) Init(typ string, logs io.Writer, abort <-chan struct{})
 
 func (tracker *tracker) Release(resource Resource) error {
 	tracker.containersL.Lock()
-	container, found := tracker.containers[resource]
+	tracked, found := tracker.containers[resource]
 	delete(tracker.containers, resource)
 	tracker.containersL.Unlock()
 
@@ -67,5 +118,17 @@ func (tracker *tracker) Release(resource Resource) error {
 		return nil
 	}
 
-	return tracker.gardenClient.Destroy(container.Handle())
+	// a script that failed, timed out, or was aborted may have left the
+	// container in any state
+	if failable, ok := resource.(interface {
+		scriptFailed() bool
+	}); ok && failable.scriptFailed() {
+		tracked.reusable = false
+	}
+
+	if tracker.pool != nil {
+		return tracker.pool.Put(tracked.typ, tracked.source, tracked.container, tracked.reusable)
+	}
+
+	return tracker.runtime.Destroy(tracked.container.Handle())
 }
@@ -2,14 +2,20 @@ package resource_test
 
 import (
 	"errors"
+	"fmt"
 
 	garden_api "github.com/cloudfoundry-incubator/garden/api"
+	gfakes "github.com/cloudfoundry-incubator/garden/api/fakes"
 	"github.com/cloudfoundry-incubator/garden/client/fake_api_client"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
 
+	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/config"
 	. "github.com/concourse/turbine/resource"
+	"github.com/concourse/turbine/resource/fakes"
+	gardenruntime "github.com/concourse/turbine/runtime/garden"
 )
 
 var _ = Describe("Tracker", func() {
@@ -30,7 +36,7 @@ var _ = Describe("Tracker", func() {
 
 		gardenClient.Connection.CreateReturns("some-handle", nil)
 
-		tracker = NewTracker(resourceTypes, gardenClient)
+		tracker = NewTracker(resourceTypes, gardenruntime.New(gardenClient), nil)
 	})
 
 	Describe("Init", func() {
@@ -46,7 +52,7 @@ var _ = Describe("Tracker", func() {
 		})
 
 		JustBeforeEach(func() {
-			initResource, initErr = tracker.Init(initType, nil, nil)
+			initResource, initErr = tracker.Init(initType, nil, nil, nil)
 		})
 
 		It("does not error and returns a resource", func() {
@@ -69,7 +75,7 @@ var _ = Describe("Tracker", func() {
 			})
 
 			It("returns the error and no resource", func() {
-				Ω(initErr).Should(Equal(disaster))
+				Ω(initErr).Should(Equal(ErrCreatingContainer{Err: disaster}))
 				Ω(initResource).Should(BeNil())
 			})
 		})
@@ -95,7 +101,7 @@ var _ = Describe("Tracker", func() {
 		BeforeEach(func() {
 			var err error
 
-			releaseResource, err = tracker.Init("type1", nil, nil)
+			releaseResource, err = tracker.Init("type1", nil, nil, nil)
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 
@@ -138,4 +144,104 @@ var _ = Describe("Tracker", func() {
 			})
 		})
 	})
+
+	Context("with a container pool", func() {
+		var handles int
+
+		source := turbine.Source{"uri": "example.com"}
+
+		BeforeEach(func() {
+			handles = 0
+			gardenClient.Connection.CreateStub = func(garden_api.ContainerSpec) (string, error) {
+				handles++
+				return fmt.Sprintf("handle-%d", handles), nil
+			}
+
+			containerRuntime := gardenruntime.New(gardenClient)
+
+			pool := NewContainerPool(
+				lagertest.NewTestLogger("test"),
+				resourceTypes,
+				containerRuntime,
+				config.Pool{MaxIdle: 1},
+				new(fakes.FakeClock),
+			)
+
+			tracker = NewTracker(resourceTypes, containerRuntime, pool)
+		})
+
+		It("reuses containers between checks", func() {
+			checker, err := tracker.InitForCheck("type1", source, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = tracker.Release(checker)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			checker, err = tracker.InitForCheck("type1", source, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(gardenClient.Connection.CreateCallCount()).Should(Equal(1))
+			Ω(gardenClient.Connection.DestroyCallCount()).Should(BeZero())
+		})
+
+		It("does not reuse containers between checks of different sources", func() {
+			checker, err := tracker.InitForCheck("type1", source, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = tracker.Release(checker)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = tracker.InitForCheck("type1", turbine.Source{"uri": "other.example.com"}, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(gardenClient.Connection.CreateCallCount()).Should(Equal(2))
+		})
+
+		It("destroys containers whose check failed rather than reusing them", func() {
+			process := new(gfakes.FakeProcess)
+			process.WaitReturns(1, nil)
+			gardenClient.Connection.RunReturns(process, nil)
+
+			checker, err := tracker.InitForCheck("type1", source, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = checker.Check(turbine.Input{Type: "type1", Source: source})
+			Ω(err).Should(HaveOccurred())
+
+			err = tracker.Release(checker)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(gardenClient.Connection.DestroyCallCount()).Should(Equal(1))
+			Ω(gardenClient.Connection.DestroyArgsForCall(0)).Should(Equal("handle-1"))
+
+			_, err = tracker.InitForCheck("type1", source, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(gardenClient.Connection.CreateCallCount()).Should(Equal(2))
+		})
+
+		It("does not hand containers used by checks to anything else", func() {
+			checker, err := tracker.InitForCheck("type1", source, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = tracker.Release(checker)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = tracker.Init("type1", nil, nil, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(gardenClient.Connection.CreateCallCount()).Should(Equal(2))
+		})
+
+		It("destroys containers used for anything but checks", func() {
+			fetcher, err := tracker.Init("type1", nil, nil, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = tracker.Release(fetcher)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(gardenClient.Connection.DestroyCallCount()).Should(Equal(1))
+			Ω(gardenClient.Connection.DestroyArgsForCall(0)).Should(Equal("handle-1"))
+		})
+	})
 })
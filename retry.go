@@ -0,0 +1,97 @@
+package turbine
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// how many times to attempt running an input's or output's resource scripts,
+// and how long to wait between attempts
+type RetryPolicy struct {
+	// total number of attempts, including the first
+	Attempts int `json:"attempts,omitempty"`
+
+	// wait before the first retry, doubling for each one after
+	Backoff Duration `json:"backoff,omitempty"`
+}
+
+// MaxAttempts is the total number of attempts to make; a nil policy makes
+// just one.
+func (policy *RetryPolicy) MaxAttempts() int {
+	if policy == nil || policy.Attempts < 1 {
+		return 1
+	}
+
+	return policy.Attempts
+}
+
+// the longest that doubling a policy's backoff may make it
+var MaxRetryBackoff = 5 * time.Minute
+
+// BackoffBefore returns how long to wait before the given attempt, starting
+// from the second. Doubling stops at MaxRetryBackoff, or at the policy's
+// backoff if it is longer.
+func (policy *RetryPolicy) BackoffBefore(attempt int) time.Duration {
+	if policy == nil || attempt < 2 {
+		return 0
+	}
+
+	backoff := time.Duration(policy.Backoff)
+
+	limit := MaxRetryBackoff
+	if backoff > limit {
+		limit = backoff
+	}
+
+	for i := 2; i < attempt && backoff > 0 && backoff < limit; i++ {
+		backoff *= 2
+	}
+
+	if backoff > limit {
+		backoff = limit
+	}
+
+	return backoff
+}
+
+func (policy *RetryPolicy) problems() []string {
+	if policy == nil {
+		return nil
+	}
+
+	problems := []string{}
+
+	if policy.Attempts < 0 {
+		problems = append(problems, "negative retry attempts")
+	}
+
+	if policy.Backoff < 0 {
+		problems = append(problems, "negative retry backoff")
+	}
+
+	return problems
+}
+
+// a time.Duration represented in JSON as a string, e.g. "1m30s"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(payload []byte) error {
+	var str string
+	err := json.Unmarshal(payload, &str)
+	if err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
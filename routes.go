@@ -3,21 +3,33 @@ package turbine
 import "github.com/tedsuo/rata"
 
 const (
-	ExecuteBuild     = "ExecuteBuild"
-	DeleteBuild      = "DeleteBuild"
-	AbortBuild       = "AbortBuild"
-	HijackBuild      = "HijackBuild"
-	GetBuildEvents   = "GetBuildEvents"
-	CheckInput       = "CheckInput"
-	CheckInputStream = "CheckInputStream"
+	ExecuteBuild      = "ExecuteBuild"
+	PlanBuild         = "PlanBuild"
+	GetBuild          = "GetBuild"
+	DeleteBuild       = "DeleteBuild"
+	AbortBuild        = "AbortBuild"
+	HijackBuild       = "HijackBuild"
+	GetBuildEvents    = "GetBuildEvents"
+	StreamBuildEvents = "StreamBuildEvents"
+	ExportBuildEvents = "ExportBuildEvents"
+	GetBuildLog       = "GetBuildLog"
+	CheckInput        = "CheckInput"
+	CheckInputStream  = "CheckInputStream"
+	WatchInput        = "WatchInput"
 )
 
 var Routes = rata.Routes{
 	{Path: "/builds", Method: "POST", Name: ExecuteBuild},
+	{Path: "/builds/plan", Method: "POST", Name: PlanBuild},
+	{Path: "/builds/:guid", Method: "GET", Name: GetBuild},
 	{Path: "/builds/:guid", Method: "DELETE", Name: DeleteBuild},
 	{Path: "/builds/:guid/abort", Method: "POST", Name: AbortBuild},
 	{Path: "/builds/:guid/hijack", Method: "POST", Name: HijackBuild},
 	{Path: "/builds/:guid/events", Method: "GET", Name: GetBuildEvents},
+	{Path: "/builds/:guid/events/stream", Method: "GET", Name: StreamBuildEvents},
+	{Path: "/builds/:guid/events.json", Method: "GET", Name: ExportBuildEvents},
+	{Path: "/builds/:guid/log", Method: "GET", Name: GetBuildLog},
 	{Path: "/checks", Method: "POST", Name: CheckInput},
 	{Path: "/checks/stream", Method: "GET", Name: CheckInputStream},
+	{Path: "/checks/watch", Method: "GET", Name: WatchInput},
 }
@@ -0,0 +1,118 @@
+package fakeruntime
+
+import (
+	"io"
+
+	gapi "github.com/cloudfoundry-incubator/garden/api"
+	gfakes "github.com/cloudfoundry-incubator/garden/api/fakes"
+	"github.com/cloudfoundry-incubator/garden/client/fake_api_client"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+// GardenClient returns a Garden client whose connection is served by the
+// runtime, so that suites can run containers through runtime/garden as they
+// would against a Garden server.
+func (r *Runtime) GardenClient() gapi.Client {
+	client := fake_api_client.New()
+
+	connection := client.Connection
+
+	connection.CreateStub = func(spec gapi.ContainerSpec) (string, error) {
+		container, err := r.Create(runtime.ContainerSpec{
+			Handle:     spec.Handle,
+			RootFSPath: spec.RootFSPath,
+			Privileged: spec.Privileged,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return container.Handle(), nil
+	}
+
+	connection.ListStub = func(gapi.Properties) ([]string, error) {
+		return r.Handles(), nil
+	}
+
+	connection.DestroyStub = r.Destroy
+
+	connection.RunStub = func(handle string, spec gapi.ProcessSpec, io gapi.ProcessIO) (gapi.Process, error) {
+		container, err := r.Lookup(handle)
+		if err != nil {
+			return nil, err
+		}
+
+		process, err := container.Run(runtime.ProcessSpec{
+			Path: spec.Path,
+			Args: spec.Args,
+			Env:  spec.Env,
+			Dir:  spec.Dir,
+
+			Privileged: spec.Privileged,
+		}, runtimeProcessIO(io))
+		if err != nil {
+			return nil, err
+		}
+
+		return gardenProcess(process), nil
+	}
+
+	connection.AttachStub = func(handle string, processID uint32, io gapi.ProcessIO) (gapi.Process, error) {
+		container, err := r.Lookup(handle)
+		if err != nil {
+			return nil, err
+		}
+
+		process, err := container.Attach(processID, runtimeProcessIO(io))
+		if err != nil {
+			return nil, err
+		}
+
+		return gardenProcess(process), nil
+	}
+
+	connection.StreamInStub = func(handle string, dstPath string, tarStream io.Reader) error {
+		container, err := r.Lookup(handle)
+		if err != nil {
+			return err
+		}
+
+		return container.StreamIn(dstPath, tarStream)
+	}
+
+	connection.StreamOutStub = func(handle string, srcPath string) (io.ReadCloser, error) {
+		container, err := r.Lookup(handle)
+		if err != nil {
+			return nil, err
+		}
+
+		return container.StreamOut(srcPath)
+	}
+
+	connection.StopStub = func(handle string, kill bool) error {
+		container, err := r.Lookup(handle)
+		if err != nil {
+			return err
+		}
+
+		return container.Stop(kill)
+	}
+
+	return client
+}
+
+func runtimeProcessIO(io gapi.ProcessIO) runtime.ProcessIO {
+	return runtime.ProcessIO{
+		Stdin:  io.Stdin,
+		Stdout: io.Stdout,
+		Stderr: io.Stderr,
+	}
+}
+
+func gardenProcess(process runtime.Process) gapi.Process {
+	fake := new(gfakes.FakeProcess)
+	fake.IDReturns(process.ID())
+	fake.WaitStub = process.Wait
+	return fake
+}
@@ -0,0 +1,128 @@
+package fakeruntime
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+// what a Script is given to run with
+type Process struct {
+	Spec runtime.ProcessSpec
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// closed when the container is stopped; scripts should return promptly
+	Stopped <-chan struct{}
+
+	id        uint32
+	container *Container
+
+	stdout *fanOut
+	stderr *fanOut
+
+	stopped  chan struct{}
+	stopOnce *sync.Once
+
+	exited     chan struct{}
+	exitStatus int
+}
+
+func newProcess(id uint32, container *Container, spec runtime.ProcessSpec, pio runtime.ProcessIO) *Process {
+	stdin := pio.Stdin
+	if stdin == nil {
+		stdin = new(bytes.Buffer)
+	}
+
+	stopped := make(chan struct{})
+
+	process := &Process{
+		Spec: spec,
+
+		Stdin:   stdin,
+		Stopped: stopped,
+
+		id:        id,
+		container: container,
+
+		stdout: &fanOut{},
+		stderr: &fanOut{},
+
+		stopped:  stopped,
+		stopOnce: new(sync.Once),
+
+		exited: make(chan struct{}),
+	}
+
+	process.Stdout = process.stdout
+	process.Stderr = process.stderr
+
+	process.attach(pio)
+
+	return process
+}
+
+// Path resolves a path within the process's container to one on the host.
+func (p *Process) Path(containerPath string) string {
+	return p.container.Path(containerPath)
+}
+
+func (p *Process) ID() uint32 {
+	return p.id
+}
+
+func (p *Process) Wait() (int, error) {
+	<-p.exited
+	return p.exitStatus, nil
+}
+
+func (p *Process) SetTTY(runtime.TTYSpec) error {
+	return nil
+}
+
+func (p *Process) run(script Script) {
+	p.exitStatus = script(p)
+	close(p.exited)
+}
+
+func (p *Process) stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopped)
+	})
+}
+
+func (p *Process) attach(pio runtime.ProcessIO) {
+	if pio.Stdout != nil {
+		p.stdout.add(pio.Stdout)
+	}
+
+	if pio.Stderr != nil {
+		p.stderr.add(pio.Stderr)
+	}
+}
+
+type fanOut struct {
+	writers []io.Writer
+	lock    sync.Mutex
+}
+
+func (f *fanOut) add(w io.Writer) {
+	f.lock.Lock()
+	f.writers = append(f.writers, w)
+	f.lock.Unlock()
+}
+
+func (f *fanOut) Write(data []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, w := range f.writers {
+		w.Write(data)
+	}
+
+	return len(data), nil
+}
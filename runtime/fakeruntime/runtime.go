@@ -0,0 +1,259 @@
+// Package fakeruntime is an in-process runtime for integration tests.
+//
+// Containers are backed by temporary directories and streamed in and out of
+// as tarballs, as with a real runtime. Processes are Go functions registered
+// by rootfs and path, standing in for the executables of an image. The
+// runtime can be used directly, or served to runtime/garden through a Garden
+// client.
+package fakeruntime
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/concourse/turbine/runtime"
+	"github.com/concourse/turbine/runtime/tarfs"
+)
+
+// a stand-in for an executable; returns the exit status
+type Script func(*Process) int
+
+type ExecutableNotFoundError struct {
+	RootFSPath string
+	Path       string
+}
+
+func (err ExecutableNotFoundError) Error() string {
+	return fmt.Sprintf("no script registered for %s in %s", err.Path, err.RootFSPath)
+}
+
+type Runtime struct {
+	depotDir string
+
+	scripts map[string]map[string]Script
+
+	containers map[string]*Container
+	nextHandle int
+
+	lock *sync.Mutex
+}
+
+func New(depotDir string) *Runtime {
+	return &Runtime{
+		depotDir: depotDir,
+
+		scripts: make(map[string]map[string]Script),
+
+		containers: make(map[string]*Container),
+
+		lock: new(sync.Mutex),
+	}
+}
+
+// Register makes running path in containers created with rootFSPath run
+// the script.
+func (r *Runtime) Register(rootFSPath string, path string, script Script) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	scripts, found := r.scripts[rootFSPath]
+	if !found {
+		scripts = make(map[string]Script)
+		r.scripts[rootFSPath] = scripts
+	}
+
+	scripts[path] = script
+}
+
+// Handles returns the handles of all containers not yet destroyed.
+func (r *Runtime) Handles() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	handles := []string{}
+	for handle := range r.containers {
+		handles = append(handles, handle)
+	}
+
+	sort.Strings(handles)
+
+	return handles
+}
+
+func (r *Runtime) Create(spec runtime.ContainerSpec) (runtime.Container, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	handle := spec.Handle
+	if handle == "" {
+		r.nextHandle++
+		handle = fmt.Sprintf("container-%d", r.nextHandle)
+	}
+
+	if _, found := r.containers[handle]; found {
+		return nil, fmt.Errorf("container already exists: %s", handle)
+	}
+
+	dir, err := ioutil.TempDir(r.depotDir, "container")
+	if err != nil {
+		return nil, err
+	}
+
+	container := &Container{
+		Spec: spec,
+
+		handle: handle,
+		dir:    dir,
+
+		runtime: r,
+
+		processes: make(map[uint32]*Process),
+		lock:      new(sync.Mutex),
+	}
+
+	r.containers[handle] = container
+
+	return container, nil
+}
+
+func (r *Runtime) Lookup(handle string) (runtime.Container, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	container, found := r.containers[handle]
+	if !found {
+		return nil, runtime.ErrContainerNotFound
+	}
+
+	return container, nil
+}
+
+func (r *Runtime) Destroy(handle string) error {
+	r.lock.Lock()
+	container, found := r.containers[handle]
+	delete(r.containers, handle)
+	r.lock.Unlock()
+
+	if !found {
+		return runtime.ErrContainerNotFound
+	}
+
+	container.Stop(true)
+
+	return os.RemoveAll(container.dir)
+}
+
+func (r *Runtime) script(rootFSPath string, path string) (Script, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	script, found := r.scripts[rootFSPath][path]
+	return script, found
+}
+
+type Container struct {
+	Spec runtime.ContainerSpec
+
+	handle string
+	dir    string
+
+	runtime *Runtime
+
+	processes map[uint32]*Process
+	nextID    uint32
+
+	lock *sync.Mutex
+}
+
+func (c *Container) Handle() string {
+	return c.handle
+}
+
+// Path resolves a path within the container to one on the host.
+func (c *Container) Path(containerPath string) string {
+	return filepath.Join(c.dir, filepath.Clean("/"+containerPath))
+}
+
+func (c *Container) Run(spec runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
+	script, found := c.runtime.script(c.Spec.RootFSPath, spec.Path)
+	if !found {
+		return nil, ExecutableNotFoundError{c.Spec.RootFSPath, spec.Path}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.nextID++
+
+	process := newProcess(c.nextID, c, spec, io)
+	c.processes[process.id] = process
+
+	go process.run(script)
+
+	return process, nil
+}
+
+func (c *Container) Attach(processID uint32, io runtime.ProcessIO) (runtime.Process, error) {
+	c.lock.Lock()
+	process, found := c.processes[processID]
+	c.lock.Unlock()
+
+	if !found {
+		return nil, runtime.ErrProcessNotFound
+	}
+
+	process.attach(io)
+
+	return process, nil
+}
+
+func (c *Container) StreamIn(dstPath string, tarStream io.Reader) error {
+	return tarfs.Extract(c.Path(dstPath), tarStream)
+}
+
+func (c *Container) StreamOut(srcPath string) (io.ReadCloser, error) {
+	src := c.Path(srcPath)
+	if strings.HasSuffix(srcPath, "/") {
+		src += "/"
+	}
+
+	_, err := os.Lstat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	tarStream, tarWriter := io.Pipe()
+
+	go func() {
+		tarWriter.CloseWithError(tarfs.Write(tarWriter, src))
+	}()
+
+	return tarStream, nil
+}
+
+// Stop signals every process's script to return via Process.Stopped, and
+// waits for them to do so.
+func (c *Container) Stop(kill bool) error {
+	c.lock.Lock()
+	processes := make([]*Process, 0, len(c.processes))
+	for _, process := range c.processes {
+		processes = append(processes, process)
+	}
+	c.lock.Unlock()
+
+	for _, process := range processes {
+		process.stop()
+	}
+
+	for _, process := range processes {
+		process.Wait()
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+type FakeContainer struct {
+	HandleStub        func() string
+	handleMutex       sync.RWMutex
+	handleArgsForCall []struct{}
+	handleReturns     struct {
+		result1 string
+	}
+	RunStub        func(runtime.ProcessSpec, runtime.ProcessIO) (runtime.Process, error)
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 runtime.ProcessSpec
+		arg2 runtime.ProcessIO
+	}
+	runReturns struct {
+		result1 runtime.Process
+		result2 error
+	}
+	AttachStub        func(processID uint32, io runtime.ProcessIO) (runtime.Process, error)
+	attachMutex       sync.RWMutex
+	attachArgsForCall []struct {
+		processID uint32
+		io        runtime.ProcessIO
+	}
+	attachReturns struct {
+		result1 runtime.Process
+		result2 error
+	}
+	StreamInStub        func(dstPath string, tarStream io.Reader) error
+	streamInMutex       sync.RWMutex
+	streamInArgsForCall []struct {
+		dstPath   string
+		tarStream io.Reader
+	}
+	streamInReturns struct {
+		result1 error
+	}
+	StreamOutStub        func(srcPath string) (io.ReadCloser, error)
+	streamOutMutex       sync.RWMutex
+	streamOutArgsForCall []struct {
+		srcPath string
+	}
+	streamOutReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	StopStub        func(kill bool) error
+	stopMutex       sync.RWMutex
+	stopArgsForCall []struct {
+		kill bool
+	}
+	stopReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeContainer) Handle() string {
+	fake.handleMutex.Lock()
+	fake.handleArgsForCall = append(fake.handleArgsForCall, struct{}{})
+	fake.handleMutex.Unlock()
+	if fake.HandleStub != nil {
+		return fake.HandleStub()
+	} else {
+		return fake.handleReturns.result1
+	}
+}
+
+func (fake *FakeContainer) HandleCallCount() int {
+	fake.handleMutex.RLock()
+	defer fake.handleMutex.RUnlock()
+	return len(fake.handleArgsForCall)
+}
+
+func (fake *FakeContainer) HandleReturns(result1 string) {
+	fake.HandleStub = nil
+	fake.handleReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeContainer) Run(arg1 runtime.ProcessSpec, arg2 runtime.ProcessIO) (runtime.Process, error) {
+	fake.runMutex.Lock()
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 runtime.ProcessSpec
+		arg2 runtime.ProcessIO
+	}{arg1, arg2})
+	fake.runMutex.Unlock()
+	if fake.RunStub != nil {
+		return fake.RunStub(arg1, arg2)
+	} else {
+		return fake.runReturns.result1, fake.runReturns.result2
+	}
+}
+
+func (fake *FakeContainer) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *FakeContainer) RunArgsForCall(i int) (runtime.ProcessSpec, runtime.ProcessIO) {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return fake.runArgsForCall[i].arg1, fake.runArgsForCall[i].arg2
+}
+
+func (fake *FakeContainer) RunReturns(result1 runtime.Process, result2 error) {
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 runtime.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) Attach(processID uint32, io runtime.ProcessIO) (runtime.Process, error) {
+	fake.attachMutex.Lock()
+	fake.attachArgsForCall = append(fake.attachArgsForCall, struct {
+		processID uint32
+		io        runtime.ProcessIO
+	}{processID, io})
+	fake.attachMutex.Unlock()
+	if fake.AttachStub != nil {
+		return fake.AttachStub(processID, io)
+	} else {
+		return fake.attachReturns.result1, fake.attachReturns.result2
+	}
+}
+
+func (fake *FakeContainer) AttachCallCount() int {
+	fake.attachMutex.RLock()
+	defer fake.attachMutex.RUnlock()
+	return len(fake.attachArgsForCall)
+}
+
+func (fake *FakeContainer) AttachArgsForCall(i int) (uint32, runtime.ProcessIO) {
+	fake.attachMutex.RLock()
+	defer fake.attachMutex.RUnlock()
+	return fake.attachArgsForCall[i].processID, fake.attachArgsForCall[i].io
+}
+
+func (fake *FakeContainer) AttachReturns(result1 runtime.Process, result2 error) {
+	fake.AttachStub = nil
+	fake.attachReturns = struct {
+		result1 runtime.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) StreamIn(dstPath string, tarStream io.Reader) error {
+	fake.streamInMutex.Lock()
+	fake.streamInArgsForCall = append(fake.streamInArgsForCall, struct {
+		dstPath   string
+		tarStream io.Reader
+	}{dstPath, tarStream})
+	fake.streamInMutex.Unlock()
+	if fake.StreamInStub != nil {
+		return fake.StreamInStub(dstPath, tarStream)
+	} else {
+		return fake.streamInReturns.result1
+	}
+}
+
+func (fake *FakeContainer) StreamInCallCount() int {
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	return len(fake.streamInArgsForCall)
+}
+
+func (fake *FakeContainer) StreamInArgsForCall(i int) (string, io.Reader) {
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	return fake.streamInArgsForCall[i].dstPath, fake.streamInArgsForCall[i].tarStream
+}
+
+func (fake *FakeContainer) StreamInReturns(result1 error) {
+	fake.StreamInStub = nil
+	fake.streamInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) StreamOut(srcPath string) (io.ReadCloser, error) {
+	fake.streamOutMutex.Lock()
+	fake.streamOutArgsForCall = append(fake.streamOutArgsForCall, struct {
+		srcPath string
+	}{srcPath})
+	fake.streamOutMutex.Unlock()
+	if fake.StreamOutStub != nil {
+		return fake.StreamOutStub(srcPath)
+	} else {
+		return fake.streamOutReturns.result1, fake.streamOutReturns.result2
+	}
+}
+
+func (fake *FakeContainer) StreamOutCallCount() int {
+	fake.streamOutMutex.RLock()
+	defer fake.streamOutMutex.RUnlock()
+	return len(fake.streamOutArgsForCall)
+}
+
+func (fake *FakeContainer) StreamOutArgsForCall(i int) string {
+	fake.streamOutMutex.RLock()
+	defer fake.streamOutMutex.RUnlock()
+	return fake.streamOutArgsForCall[i].srcPath
+}
+
+func (fake *FakeContainer) StreamOutReturns(result1 io.ReadCloser, result2 error) {
+	fake.StreamOutStub = nil
+	fake.streamOutReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) Stop(kill bool) error {
+	fake.stopMutex.Lock()
+	fake.stopArgsForCall = append(fake.stopArgsForCall, struct {
+		kill bool
+	}{kill})
+	fake.stopMutex.Unlock()
+	if fake.StopStub != nil {
+		return fake.StopStub(kill)
+	} else {
+		return fake.stopReturns.result1
+	}
+}
+
+func (fake *FakeContainer) StopCallCount() int {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	return len(fake.stopArgsForCall)
+}
+
+func (fake *FakeContainer) StopArgsForCall(i int) bool {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	return fake.stopArgsForCall[i].kill
+}
+
+func (fake *FakeContainer) StopReturns(result1 error) {
+	fake.StopStub = nil
+	fake.stopReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ runtime.Container = new(FakeContainer)
@@ -0,0 +1,137 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+type FakeRuntime struct {
+	CreateStub        func(runtime.ContainerSpec) (runtime.Container, error)
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 runtime.ContainerSpec
+	}
+	createReturns struct {
+		result1 runtime.Container
+		result2 error
+	}
+	LookupStub        func(handle string) (runtime.Container, error)
+	lookupMutex       sync.RWMutex
+	lookupArgsForCall []struct {
+		handle string
+	}
+	lookupReturns struct {
+		result1 runtime.Container
+		result2 error
+	}
+	DestroyStub        func(handle string) error
+	destroyMutex       sync.RWMutex
+	destroyArgsForCall []struct {
+		handle string
+	}
+	destroyReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeRuntime) Create(arg1 runtime.ContainerSpec) (runtime.Container, error) {
+	fake.createMutex.Lock()
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 runtime.ContainerSpec
+	}{arg1})
+	fake.createMutex.Unlock()
+	if fake.CreateStub != nil {
+		return fake.CreateStub(arg1)
+	} else {
+		return fake.createReturns.result1, fake.createReturns.result2
+	}
+}
+
+func (fake *FakeRuntime) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+func (fake *FakeRuntime) CreateArgsForCall(i int) runtime.ContainerSpec {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return fake.createArgsForCall[i].arg1
+}
+
+func (fake *FakeRuntime) CreateReturns(result1 runtime.Container, result2 error) {
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 runtime.Container
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRuntime) Lookup(handle string) (runtime.Container, error) {
+	fake.lookupMutex.Lock()
+	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.lookupMutex.Unlock()
+	if fake.LookupStub != nil {
+		return fake.LookupStub(handle)
+	} else {
+		return fake.lookupReturns.result1, fake.lookupReturns.result2
+	}
+}
+
+func (fake *FakeRuntime) LookupCallCount() int {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return len(fake.lookupArgsForCall)
+}
+
+func (fake *FakeRuntime) LookupArgsForCall(i int) string {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return fake.lookupArgsForCall[i].handle
+}
+
+func (fake *FakeRuntime) LookupReturns(result1 runtime.Container, result2 error) {
+	fake.LookupStub = nil
+	fake.lookupReturns = struct {
+		result1 runtime.Container
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRuntime) Destroy(handle string) error {
+	fake.destroyMutex.Lock()
+	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.destroyMutex.Unlock()
+	if fake.DestroyStub != nil {
+		return fake.DestroyStub(handle)
+	} else {
+		return fake.destroyReturns.result1
+	}
+}
+
+func (fake *FakeRuntime) DestroyCallCount() int {
+	fake.destroyMutex.RLock()
+	defer fake.destroyMutex.RUnlock()
+	return len(fake.destroyArgsForCall)
+}
+
+func (fake *FakeRuntime) DestroyArgsForCall(i int) string {
+	fake.destroyMutex.RLock()
+	defer fake.destroyMutex.RUnlock()
+	return fake.destroyArgsForCall[i].handle
+}
+
+func (fake *FakeRuntime) DestroyReturns(result1 error) {
+	fake.DestroyStub = nil
+	fake.destroyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ runtime.Runtime = new(FakeRuntime)
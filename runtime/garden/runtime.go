@@ -0,0 +1,132 @@
+// Package garden runs containers on a Garden server.
+package garden
+
+import (
+	"io"
+
+	gapi "github.com/cloudfoundry-incubator/garden/api"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+type gardenRuntime struct {
+	client gapi.Client
+}
+
+func New(client gapi.Client) runtime.Runtime {
+	return &gardenRuntime{client: client}
+}
+
+func (r *gardenRuntime) Create(spec runtime.ContainerSpec) (runtime.Container, error) {
+	container, err := r.client.Create(gapi.ContainerSpec{
+		Handle:     spec.Handle,
+		RootFSPath: spec.RootFSPath,
+		Privileged: spec.Privileged,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenContainer{container}, nil
+}
+
+func (r *gardenRuntime) Lookup(handle string) (runtime.Container, error) {
+	container, err := r.client.Lookup(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenContainer{container}, nil
+}
+
+func (r *gardenRuntime) Destroy(handle string) error {
+	return r.client.Destroy(handle)
+}
+
+type gardenContainer struct {
+	container gapi.Container
+}
+
+func (c *gardenContainer) Handle() string {
+	return c.container.Handle()
+}
+
+func (c *gardenContainer) Run(spec runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
+	process, err := c.container.Run(gapi.ProcessSpec{
+		Path: spec.Path,
+		Args: spec.Args,
+		Env:  spec.Env,
+		Dir:  spec.Dir,
+
+		Privileged: spec.Privileged,
+
+		TTY: gardenTTYSpec(spec.TTY),
+	}, gardenProcessIO(io))
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenProcess{process}, nil
+}
+
+func (c *gardenContainer) Attach(processID uint32, io runtime.ProcessIO) (runtime.Process, error) {
+	process, err := c.container.Attach(processID, gardenProcessIO(io))
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenProcess{process}, nil
+}
+
+func (c *gardenContainer) StreamIn(dstPath string, tarStream io.Reader) error {
+	return c.container.StreamIn(dstPath, tarStream)
+}
+
+func (c *gardenContainer) StreamOut(srcPath string) (io.ReadCloser, error) {
+	return c.container.StreamOut(srcPath)
+}
+
+func (c *gardenContainer) Stop(kill bool) error {
+	return c.container.Stop(kill)
+}
+
+type gardenProcess struct {
+	process gapi.Process
+}
+
+func (p *gardenProcess) ID() uint32 {
+	return p.process.ID()
+}
+
+func (p *gardenProcess) Wait() (int, error) {
+	return p.process.Wait()
+}
+
+func (p *gardenProcess) SetTTY(spec runtime.TTYSpec) error {
+	return p.process.SetTTY(*gardenTTYSpec(&spec))
+}
+
+func gardenProcessIO(io runtime.ProcessIO) gapi.ProcessIO {
+	return gapi.ProcessIO{
+		Stdin:  io.Stdin,
+		Stdout: io.Stdout,
+		Stderr: io.Stderr,
+	}
+}
+
+func gardenTTYSpec(spec *runtime.TTYSpec) *gapi.TTYSpec {
+	if spec == nil {
+		return nil
+	}
+
+	tty := &gapi.TTYSpec{}
+
+	if spec.WindowSize != nil {
+		tty.WindowSize = &gapi.WindowSize{
+			Columns: spec.WindowSize.Columns,
+			Rows:    spec.WindowSize.Rows,
+		}
+	}
+
+	return tty
+}
@@ -0,0 +1,173 @@
+package local
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/concourse/turbine/runtime"
+	"github.com/concourse/turbine/runtime/tarfs"
+)
+
+// how long processes have to exit after SIGTERM before being killed
+const StopGraceTime = 10 * time.Second
+
+// where executables given without a path are searched for
+var searchPath = []string{
+	"/usr/local/sbin",
+	"/usr/local/bin",
+	"/usr/sbin",
+	"/usr/bin",
+	"/sbin",
+	"/bin",
+}
+
+type container struct {
+	handle string
+	dir    string
+
+	processes  map[uint32]*process
+	nextID     uint32
+	processesL *sync.Mutex
+}
+
+func newContainer(handle string, dir string) *container {
+	return &container{
+		handle: handle,
+		dir:    dir,
+
+		processes:  make(map[uint32]*process),
+		nextID:     1,
+		processesL: new(sync.Mutex),
+	}
+}
+
+func (c *container) Handle() string {
+	return c.handle
+}
+
+// Run ignores spec.Privileged and spec.TTY; processes always run as
+// turbine's own user, without a tty. They don't inherit turbine's
+// environment; PATH defaults to searchPath.
+func (c *container) Run(spec runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
+	dir := spec.Dir
+	if dir == "" {
+		dir = "/"
+	}
+
+	cmd := &exec.Cmd{
+		Path: c.lookPath(spec.Path),
+		Args: append([]string{spec.Path}, spec.Args...),
+		Env:  append([]string{"PATH=" + strings.Join(searchPath, ":")}, spec.Env...),
+		Dir:  dir,
+
+		SysProcAttr: processAttr(c.dir),
+	}
+
+	c.processesL.Lock()
+	defer c.processesL.Unlock()
+
+	process, err := startProcess(c.nextID, cmd, io)
+	if err != nil {
+		return nil, err
+	}
+
+	c.processes[process.id] = process
+	c.nextID++
+
+	return process, nil
+}
+
+func (c *container) Attach(processID uint32, io runtime.ProcessIO) (runtime.Process, error) {
+	c.processesL.Lock()
+	process, found := c.processes[processID]
+	c.processesL.Unlock()
+
+	if !found {
+		return nil, runtime.ErrProcessNotFound
+	}
+
+	process.attach(io)
+
+	return process, nil
+}
+
+func (c *container) StreamIn(dstPath string, tarStream io.Reader) error {
+	return tarfs.Extract(c.path(dstPath), tarStream)
+}
+
+func (c *container) StreamOut(srcPath string) (io.ReadCloser, error) {
+	src := c.path(srcPath)
+	if strings.HasSuffix(srcPath, "/") {
+		src += "/"
+	}
+
+	_, err := os.Lstat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	tarStream, tarWriter := io.Pipe()
+
+	go func() {
+		tarWriter.CloseWithError(tarfs.Write(tarWriter, src))
+	}()
+
+	return tarStream, nil
+}
+
+func (c *container) Stop(kill bool) error {
+	c.processesL.Lock()
+	processes := make([]*process, 0, len(c.processes))
+	for _, process := range c.processes {
+		processes = append(processes, process)
+	}
+	c.processesL.Unlock()
+
+	signal := syscall.SIGTERM
+	if kill {
+		signal = syscall.SIGKILL
+	}
+
+	for _, process := range processes {
+		process.signal(signal)
+	}
+
+	grace := time.After(StopGraceTime)
+
+	for _, process := range processes {
+		select {
+		case <-process.exited:
+		case <-grace:
+			process.signal(syscall.SIGKILL)
+			<-process.exited
+		}
+	}
+
+	return nil
+}
+
+// path resolves a path within the container to one on the host
+func (c *container) path(containerPath string) string {
+	return filepath.Join(c.dir, filepath.Clean("/"+containerPath))
+}
+
+func (c *container) lookPath(file string) string {
+	if strings.Contains(file, "/") {
+		return file
+	}
+
+	for _, dir := range searchPath {
+		info, err := os.Stat(c.path(filepath.Join(dir, file)))
+		if err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return filepath.Join(dir, file)
+		}
+	}
+
+	return file
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// prints its working directory, args, environment, and stdin, then exits
+// with $EXIT_STATUS
+func main() {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("dir: %s\n", dir)
+	fmt.Printf("args: %v\n", os.Args[1:])
+	fmt.Printf("env: %v\n", os.Environ())
+
+	io.Copy(os.Stdout, os.Stdin)
+
+	fmt.Fprintf(os.Stderr, "hello from stderr\n")
+
+	status, _ := strconv.Atoi(os.Getenv("EXIT_STATUS"))
+	os.Exit(status)
+}
@@ -0,0 +1,31 @@
+package local_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"testing"
+)
+
+var helloPath string
+
+var _ = BeforeSuite(func() {
+	// must be static to run in a bare chroot
+	os.Setenv("CGO_ENABLED", "0")
+
+	var err error
+	helloPath, err = gexec.Build("github.com/concourse/turbine/runtime/local/fixtures/hello")
+	Ω(err).ShouldNot(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	gexec.CleanupBuildArtifacts()
+})
+
+func TestLocal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Local Runtime Suite")
+}
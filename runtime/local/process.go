@@ -0,0 +1,137 @@
+package local
+
+import (
+	"io"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/concourse/turbine/runtime"
+)
+
+type process struct {
+	id  uint32
+	cmd *exec.Cmd
+
+	stdout *fanOut
+	stderr *fanOut
+
+	exited     chan struct{}
+	exitStatus int
+	exitErr    error
+}
+
+func startProcess(id uint32, cmd *exec.Cmd, pio runtime.ProcessIO) (*process, error) {
+	process := &process{
+		id:  id,
+		cmd: cmd,
+
+		stdout: &fanOut{},
+		stderr: &fanOut{},
+
+		exited: make(chan struct{}),
+	}
+
+	process.attach(pio)
+
+	cmd.Stdout = process.stdout
+	cmd.Stderr = process.stderr
+
+	// copy stdin ourselves; exec would otherwise wait on it to reach EOF
+	// before considering the process exited
+	var stdin io.WriteCloser
+	if pio.Stdin != nil {
+		var err error
+		stdin, err = cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	if stdin != nil {
+		go func() {
+			io.Copy(stdin, pio.Stdin)
+			stdin.Close()
+		}()
+	}
+
+	go process.wait()
+
+	return process, nil
+}
+
+func (p *process) ID() uint32 {
+	return p.id
+}
+
+func (p *process) Wait() (int, error) {
+	<-p.exited
+	return p.exitStatus, p.exitErr
+}
+
+// SetTTY is a no-op, as processes are not run with a tty.
+func (p *process) SetTTY(runtime.TTYSpec) error {
+	return nil
+}
+
+func (p *process) attach(pio runtime.ProcessIO) {
+	if pio.Stdout != nil {
+		p.stdout.add(pio.Stdout)
+	}
+
+	if pio.Stderr != nil {
+		p.stderr.add(pio.Stderr)
+	}
+}
+
+func (p *process) signal(signal syscall.Signal) {
+	select {
+	case <-p.exited:
+	default:
+		syscall.Kill(-p.cmd.Process.Pid, signal)
+	}
+}
+
+func (p *process) wait() {
+	err := p.cmd.Wait()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		status := exitErr.Sys().(syscall.WaitStatus)
+		if status.Signaled() {
+			p.exitStatus = 128 + int(status.Signal())
+		} else {
+			p.exitStatus = status.ExitStatus()
+		}
+	} else {
+		p.exitErr = err
+	}
+
+	close(p.exited)
+}
+
+// fanOut writes to every attached writer, ignoring those that fail
+type fanOut struct {
+	writers []io.Writer
+	lock    sync.Mutex
+}
+
+func (f *fanOut) add(w io.Writer) {
+	f.lock.Lock()
+	f.writers = append(f.writers, w)
+	f.lock.Unlock()
+}
+
+func (f *fanOut) Write(data []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, w := range f.writers {
+		w.Write(data)
+	}
+
+	return len(data), nil
+}
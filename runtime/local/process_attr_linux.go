@@ -0,0 +1,26 @@
+package local
+
+import (
+	"os"
+	"syscall"
+)
+
+// processAttr chroots processes into the container's directory. Chrooting
+// requires root, so without it they're run as root within a user namespace
+// of their own, mapped to turbine's user.
+func processAttr(dir string) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{
+		Chroot: dir,
+
+		// so that stopping signals the whole process group
+		Setpgid: true,
+	}
+
+	if os.Getuid() != 0 {
+		attr.Cloneflags = syscall.CLONE_NEWUSER
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	}
+
+	return attr
+}
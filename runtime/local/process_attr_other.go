@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package local
+
+import "syscall"
+
+// processAttr chroots processes into the container's directory, which
+// requires root.
+func processAttr(dir string) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Chroot: dir,
+
+		// so that stopping signals the whole process group
+		Setpgid: true,
+	}
+}
@@ -0,0 +1,161 @@
+// Package local runs containers as chrooted processes on the local machine.
+//
+// It provides no isolation beyond the chroot and runs every process as
+// turbine's own user, which must be root except on Linux, where processes are
+// chrooted within a user namespace instead. It is intended for development
+// and integration testing, not for running untrusted builds.
+package local
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nu7hatch/gouuid"
+
+	"github.com/concourse/turbine/runtime"
+	"github.com/concourse/turbine/runtime/tarfs"
+)
+
+var ErrContainerExists = errors.New("container already exists")
+
+type UnsupportedRootFSError struct {
+	RootFSPath string
+}
+
+func (err UnsupportedRootFSError) Error() string {
+	return fmt.Sprintf("unsupported rootfs (must be a local directory): %s", err.RootFSPath)
+}
+
+type localRuntime struct {
+	depotDir string
+
+	containers  map[string]*container
+	containersL *sync.Mutex
+}
+
+// New returns a runtime keeping each container's filesystem in a directory
+// under depotDir.
+func New(depotDir string) runtime.Runtime {
+	return &localRuntime{
+		depotDir: depotDir,
+
+		containers:  make(map[string]*container),
+		containersL: new(sync.Mutex),
+	}
+}
+
+func (r *localRuntime) Create(spec runtime.ContainerSpec) (runtime.Container, error) {
+	rootfs, err := rootFSDir(spec.RootFSPath)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := spec.Handle
+	if handle == "" {
+		guid, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+
+		handle = guid.String()
+	}
+
+	err = os.MkdirAll(r.depotDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(r.depotDir, handle)
+
+	err = os.Mkdir(dir, 0755)
+	if os.IsExist(err) {
+		return nil, ErrContainerExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	if rootfs != "" {
+		err = tarfs.Copy(rootfs, dir)
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, err
+		}
+	}
+
+	container := newContainer(handle, dir)
+
+	r.containersL.Lock()
+	r.containers[handle] = container
+	r.containersL.Unlock()
+
+	return container, nil
+}
+
+func (r *localRuntime) Lookup(handle string) (runtime.Container, error) {
+	r.containersL.Lock()
+	defer r.containersL.Unlock()
+
+	container, found := r.containers[handle]
+	if found {
+		return container, nil
+	}
+
+	// the container may have been created before turbine restarted, in which
+	// case its processes are no longer known
+	dir := filepath.Join(r.depotDir, handle)
+
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, runtime.ErrContainerNotFound
+	}
+
+	container = newContainer(handle, dir)
+	r.containers[handle] = container
+
+	return container, nil
+}
+
+func (r *localRuntime) Destroy(handle string) error {
+	container, err := r.Lookup(handle)
+	if err != nil {
+		return err
+	}
+
+	err = container.Stop(true)
+	if err != nil {
+		return err
+	}
+
+	r.containersL.Lock()
+	delete(r.containers, handle)
+	r.containersL.Unlock()
+
+	return os.RemoveAll(filepath.Join(r.depotDir, handle))
+}
+
+// rootFSDir accepts either a plain path or a raw:// URL to a directory
+func rootFSDir(rootFSPath string) (string, error) {
+	if rootFSPath == "" {
+		return "", nil
+	}
+
+	rootfsURL, err := url.Parse(rootFSPath)
+	if err != nil {
+		return "", err
+	}
+
+	if rootfsURL.Scheme != "" && rootfsURL.Scheme != "raw" {
+		return "", UnsupportedRootFSError{rootFSPath}
+	}
+
+	info, err := os.Stat(rootfsURL.Path)
+	if err != nil || !info.IsDir() {
+		return "", UnsupportedRootFSError{rootFSPath}
+	}
+
+	return rootfsURL.Path, nil
+}
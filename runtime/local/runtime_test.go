@@ -0,0 +1,354 @@
+package local_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/concourse/turbine/runtime"
+	. "github.com/concourse/turbine/runtime/local"
+)
+
+var _ = Describe("Local Runtime", func() {
+	var (
+		depotDir  string
+		rootfsDir string
+
+		localRuntime runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		depotDir, err = ioutil.TempDir("", "depot")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		rootfsDir, err = ioutil.TempDir("", "rootfs")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = os.MkdirAll(filepath.Join(rootfsDir, "bin"), 0755)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		hello, err := ioutil.ReadFile(helloPath)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(rootfsDir, "bin", "hello"), hello, 0755)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		localRuntime = New(depotDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(depotDir)
+		os.RemoveAll(rootfsDir)
+	})
+
+	Describe("Create", func() {
+		It("creates a container with the given handle and a copy of the rootfs", func() {
+			container, err := localRuntime.Create(runtime.ContainerSpec{
+				Handle:     "some-handle",
+				RootFSPath: "raw://" + rootfsDir,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(container.Handle()).Should(Equal("some-handle"))
+			Ω(filepath.Join(depotDir, "some-handle", "bin", "hello")).Should(BeARegularFile())
+		})
+
+		It("generates a handle if none is given", func() {
+			container, err := localRuntime.Create(runtime.ContainerSpec{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(container.Handle()).ShouldNot(BeEmpty())
+			Ω(filepath.Join(depotDir, container.Handle())).Should(BeADirectory())
+		})
+
+		It("fails if the handle is taken", func() {
+			_, err := localRuntime.Create(runtime.ContainerSpec{Handle: "some-handle"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = localRuntime.Create(runtime.ContainerSpec{Handle: "some-handle"})
+			Ω(err).Should(Equal(ErrContainerExists))
+		})
+
+		It("fails for rootfses that are not local directories", func() {
+			_, err := localRuntime.Create(runtime.ContainerSpec{
+				RootFSPath: "docker:///some/image",
+			})
+			Ω(err).Should(Equal(UnsupportedRootFSError{"docker:///some/image"}))
+		})
+	})
+
+	Describe("Lookup", func() {
+		It("finds created containers", func() {
+			created, err := localRuntime.Create(runtime.ContainerSpec{Handle: "some-handle"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			container, err := localRuntime.Lookup("some-handle")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(container).Should(Equal(created))
+		})
+
+		It("finds containers created by a previous runtime", func() {
+			_, err := localRuntime.Create(runtime.ContainerSpec{Handle: "some-handle"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			container, err := New(depotDir).Lookup("some-handle")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(container.Handle()).Should(Equal("some-handle"))
+		})
+
+		It("fails for unknown containers", func() {
+			_, err := localRuntime.Lookup("bogus-handle")
+			Ω(err).Should(Equal(runtime.ErrContainerNotFound))
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("removes the container", func() {
+			_, err := localRuntime.Create(runtime.ContainerSpec{Handle: "some-handle"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = localRuntime.Destroy("some-handle")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(filepath.Join(depotDir, "some-handle")).ShouldNot(BeAnExistingFile())
+
+			_, err = localRuntime.Lookup("some-handle")
+			Ω(err).Should(Equal(runtime.ErrContainerNotFound))
+		})
+	})
+
+	Describe("a container", func() {
+		var container runtime.Container
+
+		BeforeEach(func() {
+			var err error
+
+			container, err = localRuntime.Create(runtime.ContainerSpec{
+				Handle:     "some-handle",
+				RootFSPath: rootfsDir,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Describe("streaming in and out", func() {
+			BeforeEach(func() {
+				buf := new(bytes.Buffer)
+				tarWriter := tar.NewWriter(buf)
+
+				contents := []byte("some-contents")
+
+				err := tarWriter.WriteHeader(&tar.Header{
+					Name:     "./some-dir/",
+					Mode:     0755,
+					Typeflag: tar.TypeDir,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = tarWriter.WriteHeader(&tar.Header{
+					Name: "./some-dir/some-file",
+					Mode: 0644,
+					Size: int64(len(contents)),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = tarWriter.Write(contents)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = tarWriter.Close()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = container.StreamIn("/tmp/build/src", buf)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("extracts into the container's filesystem", func() {
+				contents, err := ioutil.ReadFile(filepath.Join(depotDir, "some-handle", "tmp", "build", "src", "some-dir", "some-file"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(contents)).Should(Equal("some-contents"))
+			})
+
+			It("streams out a directory's contents when given a trailing slash", func() {
+				Ω(tarEntries(container, "/tmp/build/src/")).Should(Equal(map[string]string{
+					"./":                   "",
+					"./some-dir/":          "",
+					"./some-dir/some-file": "some-contents",
+				}))
+			})
+
+			It("streams out a file or directory itself otherwise", func() {
+				Ω(tarEntries(container, "/tmp/build/src/some-dir/some-file")).Should(Equal(map[string]string{
+					"some-file": "some-contents",
+				}))
+
+				Ω(tarEntries(container, "/tmp/build/src/some-dir")).Should(Equal(map[string]string{
+					"some-dir/":          "",
+					"some-dir/some-file": "some-contents",
+				}))
+			})
+
+			It("does not extract through symlinks leading outside of the container", func() {
+				outsideDir, err := ioutil.TempDir("", "outside")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				defer os.RemoveAll(outsideDir)
+
+				buf := new(bytes.Buffer)
+				tarWriter := tar.NewWriter(buf)
+
+				err = tarWriter.WriteHeader(&tar.Header{
+					Name:     "./escape",
+					Typeflag: tar.TypeSymlink,
+					Linkname: outsideDir,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = tarWriter.WriteHeader(&tar.Header{
+					Name: "./escape/some-file",
+					Mode: 0644,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = tarWriter.Close()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = container.StreamIn("/tmp/build/src", buf)
+				Ω(err).Should(HaveOccurred())
+
+				Ω(filepath.Join(outsideDir, "some-file")).ShouldNot(BeAnExistingFile())
+			})
+
+			It("does not resolve paths outside of the container", func() {
+				Ω(tarEntries(container, "/../../tmp/build/src/some-dir/some-file")).Should(Equal(map[string]string{
+					"some-file": "some-contents",
+				}))
+			})
+
+			It("fails to stream out paths that do not exist", func() {
+				_, err := container.StreamOut("/bogus")
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+
+		Describe("running a process", func() {
+			var stdout *gbytes.Buffer
+			var stderr *gbytes.Buffer
+
+			BeforeEach(func() {
+				if os.Getuid() != 0 && goruntime.GOOS != "linux" {
+					Skip("running processes without root requires user namespaces")
+				}
+
+				stdout = gbytes.NewBuffer()
+				stderr = gbytes.NewBuffer()
+			})
+
+			It("runs it chrooted into the container, in the given directory", func() {
+				err := os.MkdirAll(filepath.Join(depotDir, "some-handle", "some", "dir"), 0755)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				process, err := container.Run(runtime.ProcessSpec{
+					Path: "hello",
+					Args: []string{"a", "b"},
+					Env:  []string{"EXIT_STATUS=3"},
+					Dir:  "/some/dir",
+				}, runtime.ProcessIO{
+					Stdin:  bytes.NewBufferString("some-stdin\n"),
+					Stdout: stdout,
+					Stderr: stderr,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(process.Wait()).Should(Equal(3))
+
+				Ω(stdout).Should(gbytes.Say("dir: /some/dir\n"))
+				Ω(stdout).Should(gbytes.Say(`args: \[a b\]\n`))
+				Ω(stdout).Should(gbytes.Say(`env: \[PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin EXIT_STATUS=3\]\n`))
+				Ω(stdout).Should(gbytes.Say("some-stdin\n"))
+				Ω(stderr).Should(gbytes.Say("hello from stderr\n"))
+			})
+
+			It("can be attached to by id", func() {
+				stdin, stdinWriter := io.Pipe()
+
+				process, err := container.Run(runtime.ProcessSpec{
+					Path: "/bin/hello",
+				}, runtime.ProcessIO{
+					Stdin: stdin,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				attached, err := container.Attach(process.ID(), runtime.ProcessIO{
+					Stdout: stdout,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = stdinWriter.Write([]byte("some-stdin\n"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Eventually(stdout).Should(gbytes.Say("some-stdin\n"))
+
+				stdinWriter.Close()
+
+				Ω(attached.Wait()).Should(Equal(0))
+			})
+
+			It("fails to attach to unknown processes", func() {
+				_, err := container.Attach(42, runtime.ProcessIO{})
+				Ω(err).Should(Equal(runtime.ErrProcessNotFound))
+			})
+
+			It("is stopped when the container is stopped", func() {
+				stdin, _ := io.Pipe()
+
+				process, err := container.Run(runtime.ProcessSpec{
+					Path: "/bin/hello",
+				}, runtime.ProcessIO{
+					Stdin: stdin,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = container.Stop(false)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(process.Wait()).Should(Equal(128 + 15))
+			})
+		})
+	})
+})
+
+func tarEntries(container runtime.Container, src string) map[string]string {
+	stream, err := container.StreamOut(src)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	defer stream.Close()
+
+	entries := map[string]string{}
+
+	tarReader := tar.NewReader(stream)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		contents, err := ioutil.ReadAll(tarReader)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		entries[header.Name] = string(contents)
+	}
+
+	return entries
+}
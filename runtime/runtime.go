@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrContainerNotFound = errors.New("container not found")
+var ErrProcessNotFound = errors.New("process not found")
+
+// a backend for creating containers in which resources and builds run
+type Runtime interface {
+	Create(ContainerSpec) (Container, error)
+	Lookup(handle string) (Container, error)
+	Destroy(handle string) error
+}
+
+type ContainerSpec struct {
+	// if empty, the runtime generates one
+	Handle string
+
+	RootFSPath string
+	Privileged bool
+}
+
+type Container interface {
+	Handle() string
+
+	Run(ProcessSpec, ProcessIO) (Process, error)
+	Attach(processID uint32, io ProcessIO) (Process, error)
+
+	// paths are absolute paths within the container; streams are tarballs
+	StreamIn(dstPath string, tarStream io.Reader) error
+	StreamOut(srcPath string) (io.ReadCloser, error)
+
+	Stop(kill bool) error
+}
+
+type ProcessSpec struct {
+	Path string
+	Args []string
+	Env  []string
+	Dir  string
+
+	Privileged bool
+
+	TTY *TTYSpec
+}
+
+type TTYSpec struct {
+	WindowSize *WindowSize
+}
+
+type WindowSize struct {
+	Columns int
+	Rows    int
+}
+
+type ProcessIO struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type Process interface {
+	ID() uint32
+	Wait() (int, error)
+	SetTTY(TTYSpec) error
+}
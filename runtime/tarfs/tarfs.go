@@ -0,0 +1,241 @@
+// Package tarfs moves directory trees in and out of tar streams.
+package tarfs
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Write streams src as a tarball. As with Garden, a trailing slash
+// streams the directory's contents rather than the directory itself.
+func Write(w io.Writer, src string) error {
+	base, root := filepath.Split(filepath.Clean(src))
+	if strings.HasSuffix(src, "/") {
+		base, root = filepath.Clean(src), "."
+	}
+
+	tarWriter := tar.NewWriter(w)
+
+	err := filepath.Walk(filepath.Join(base, root), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		if root == "." && name != "." {
+			name = "./" + name
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
+
+// Extract writes the tarball's regular files, directories, and links to
+// dst, creating it if necessary. Entries may not be written outside of dst,
+// whether by their names or through symlinks extracted before them.
+func Extract(dst string, tarStream io.Reader) error {
+	err := os.MkdirAll(dst, 0755)
+	if err != nil {
+		return err
+	}
+
+	// entries are compared against dst once symlinks are resolved
+	dst, err = filepath.Abs(dst)
+	if err == nil {
+		dst, err = filepath.EvalSymlinks(dst)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	tarReader := tar.NewReader(tarStream)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dst, header.Name)
+		if !within(dst, path) {
+			return fmt.Errorf("tar entry escapes destination: %s", header.Name)
+		}
+
+		// directories are created through whatever is at their path; anything
+		// else replaces it, so only its parent may not lead outside of dst
+		resolve := filepath.Dir(path)
+		if header.Typeflag == tar.TypeDir {
+			resolve = path
+		}
+
+		err = checkResolved(dst, resolve, header.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, mode)
+			if err == nil {
+				err = os.Chmod(path, mode)
+			}
+
+		case tar.TypeReg, tar.TypeRegA:
+			err = removeSymlink(path)
+			if err == nil {
+				err = writeFile(path, mode, tarReader)
+			}
+
+		case tar.TypeSymlink:
+			err = os.MkdirAll(filepath.Dir(path), 0755)
+			if err == nil {
+				os.Remove(path)
+				err = os.Symlink(header.Linkname, path)
+			}
+
+		case tar.TypeLink:
+			target := filepath.Join(dst, header.Linkname)
+			if !within(dst, target) {
+				return fmt.Errorf("tar entry links outside of destination: %s", header.Name)
+			}
+
+			err = checkResolved(dst, filepath.Dir(target), header.Name)
+			if err == nil {
+				err = os.MkdirAll(filepath.Dir(path), 0755)
+			}
+
+			if err == nil {
+				os.Remove(path)
+				err = os.Link(target, path)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func within(dst string, path string) bool {
+	return path == dst || strings.HasPrefix(path, dst+"/")
+}
+
+// checkResolved fails if path leads outside of dst once symlinks are
+// resolved. Only the part of it that exists is resolved, as the rest cannot
+// be symlinks.
+func checkResolved(dst string, path string, name string) error {
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			if !within(dst, resolved) {
+				return fmt.Errorf("tar entry escapes destination through a symlink: %s", name)
+			}
+
+			return nil
+		}
+
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		path = filepath.Dir(path)
+	}
+}
+
+// removeSymlink removes a symlink at path, so that writing there does not
+// follow it
+func removeSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+
+	return os.Remove(path)
+}
+
+func writeFile(path string, mode os.FileMode, contents io.Reader) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(file, contents)
+	return err
+}
+
+// Copy copies the contents of the src directory into dst.
+func Copy(src string, dst string) error {
+	tarStream, tarWriter := io.Pipe()
+
+	go func() {
+		tarWriter.CloseWithError(Write(tarWriter, src+"/"))
+	}()
+
+	err := Extract(dst, tarStream)
+
+	tarStream.Close()
+
+	return err
+}
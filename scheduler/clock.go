@@ -4,6 +4,7 @@ import "time"
 
 type Clock interface {
 	CurrentTime() time.Time
+	After(time.Duration) <-chan time.Time
 }
 
 func NewClock() Clock {
@@ -15,3 +16,7 @@ type realClock struct{}
 func (realClock) CurrentTime() time.Time {
 	return time.Now()
 }
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
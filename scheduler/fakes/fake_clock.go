@@ -15,6 +15,14 @@ type FakeClock struct {
 	currentTimeReturns     struct {
 		result1 time.Time
 	}
+	AfterStub        func(time.Duration) <-chan time.Time
+	afterMutex       sync.RWMutex
+	afterArgsForCall []struct {
+		arg1 time.Duration
+	}
+	afterReturns struct {
+		result1 <-chan time.Time
+	}
 }
 
 func (fake *FakeClock) CurrentTime() time.Time {
@@ -41,4 +49,36 @@ func (fake *FakeClock) CurrentTimeReturns(result1 time.Time) {
 	}{result1}
 }
 
+func (fake *FakeClock) After(arg1 time.Duration) <-chan time.Time {
+	fake.afterMutex.Lock()
+	fake.afterArgsForCall = append(fake.afterArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	fake.afterMutex.Unlock()
+	if fake.AfterStub != nil {
+		return fake.AfterStub(arg1)
+	} else {
+		return fake.afterReturns.result1
+	}
+}
+
+func (fake *FakeClock) AfterCallCount() int {
+	fake.afterMutex.RLock()
+	defer fake.afterMutex.RUnlock()
+	return len(fake.afterArgsForCall)
+}
+
+func (fake *FakeClock) AfterArgsForCall(i int) time.Duration {
+	fake.afterMutex.RLock()
+	defer fake.afterMutex.RUnlock()
+	return fake.afterArgsForCall[i].arg1
+}
+
+func (fake *FakeClock) AfterReturns(result1 <-chan time.Time) {
+	fake.AfterStub = nil
+	fake.afterReturns = struct {
+		result1 <-chan time.Time
+	}{result1}
+}
+
 var _ scheduler.Clock = new(FakeClock)
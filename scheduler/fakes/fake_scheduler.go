@@ -4,9 +4,9 @@ package fakes
 import (
 	"sync"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/runtime"
 	"github.com/concourse/turbine/scheduler"
 )
 
@@ -26,15 +26,15 @@ type FakeScheduler struct {
 	abortArgsForCall []struct {
 		guid string
 	}
-	HijackStub        func(guid string, process garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
+	HijackStub        func(guid string, process runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error)
 	hijackMutex       sync.RWMutex
 	hijackArgsForCall []struct {
 		guid    string
-		process garden.ProcessSpec
-		io      garden.ProcessIO
+		process runtime.ProcessSpec
+		io      runtime.ProcessIO
 	}
 	hijackReturns struct {
-		result1 garden.Process
+		result1 runtime.Process
 		result2 error
 	}
 	SubscribeStub        func(guid string, from uint) (<-chan event.Event, chan<- struct{}, error)
@@ -48,6 +48,15 @@ type FakeScheduler struct {
 		result2 chan<- struct{}
 		result3 error
 	}
+	LookupStub        func(guid string) (scheduler.ScheduledBuild, bool)
+	lookupMutex       sync.RWMutex
+	lookupArgsForCall []struct {
+		guid string
+	}
+	lookupReturns struct {
+		result1 scheduler.ScheduledBuild
+		result2 bool
+	}
 	DeleteStub        func(guid string)
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
@@ -130,12 +139,12 @@ func (fake *FakeScheduler) AbortArgsForCall(i int) string {
 	return fake.abortArgsForCall[i].guid
 }
 
-func (fake *FakeScheduler) Hijack(guid string, process garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+func (fake *FakeScheduler) Hijack(guid string, process runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
 	fake.hijackMutex.Lock()
 	fake.hijackArgsForCall = append(fake.hijackArgsForCall, struct {
 		guid    string
-		process garden.ProcessSpec
-		io      garden.ProcessIO
+		process runtime.ProcessSpec
+		io      runtime.ProcessIO
 	}{guid, process, io})
 	fake.hijackMutex.Unlock()
 	if fake.HijackStub != nil {
@@ -151,16 +160,16 @@ func (fake *FakeScheduler) HijackCallCount() int {
 	return len(fake.hijackArgsForCall)
 }
 
-func (fake *FakeScheduler) HijackArgsForCall(i int) (string, garden.ProcessSpec, garden.ProcessIO) {
+func (fake *FakeScheduler) HijackArgsForCall(i int) (string, runtime.ProcessSpec, runtime.ProcessIO) {
 	fake.hijackMutex.RLock()
 	defer fake.hijackMutex.RUnlock()
 	return fake.hijackArgsForCall[i].guid, fake.hijackArgsForCall[i].process, fake.hijackArgsForCall[i].io
 }
 
-func (fake *FakeScheduler) HijackReturns(result1 garden.Process, result2 error) {
+func (fake *FakeScheduler) HijackReturns(result1 runtime.Process, result2 error) {
 	fake.HijackStub = nil
 	fake.hijackReturns = struct {
-		result1 garden.Process
+		result1 runtime.Process
 		result2 error
 	}{result1, result2}
 }
@@ -200,6 +209,39 @@ func (fake *FakeScheduler) SubscribeReturns(result1 <-chan event.Event, result2
 	}{result1, result2, result3}
 }
 
+func (fake *FakeScheduler) Lookup(guid string) (scheduler.ScheduledBuild, bool) {
+	fake.lookupMutex.Lock()
+	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.lookupMutex.Unlock()
+	if fake.LookupStub != nil {
+		return fake.LookupStub(guid)
+	} else {
+		return fake.lookupReturns.result1, fake.lookupReturns.result2
+	}
+}
+
+func (fake *FakeScheduler) LookupCallCount() int {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return len(fake.lookupArgsForCall)
+}
+
+func (fake *FakeScheduler) LookupArgsForCall(i int) string {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return fake.lookupArgsForCall[i].guid
+}
+
+func (fake *FakeScheduler) LookupReturns(result1 scheduler.ScheduledBuild, result2 bool) {
+	fake.LookupStub = nil
+	fake.lookupReturns = struct {
+		result1 scheduler.ScheduledBuild
+		result2 bool
+	}{result1, result2}
+}
+
 func (fake *FakeScheduler) Delete(guid string) {
 	fake.deleteMutex.Lock()
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
@@ -5,10 +5,10 @@ import (
 	"net/http"
 	"sync"
 
-	gapi "github.com/cloudfoundry-incubator/garden/api"
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/builder"
 	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/runtime"
 	"github.com/pivotal-golang/lager"
 )
 
@@ -16,8 +16,9 @@ type Scheduler interface {
 	Start(turbine.Build)
 	Restore(ScheduledBuild)
 	Abort(guid string)
-	Hijack(guid string, process gapi.ProcessSpec, io gapi.ProcessIO) (gapi.Process, error)
+	Hijack(guid string, process runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error)
 	Subscribe(guid string, from uint) (<-chan event.Event, chan<- struct{}, error)
+	Lookup(guid string) (ScheduledBuild, bool)
 	Delete(guid string)
 
 	Drain() []ScheduledBuild
@@ -29,6 +30,9 @@ type ScheduledBuild struct {
 	ProcessID uint32
 	EventHub  *event.Hub
 
+	// secrets of a restored build, whose secret params aren't snapshotted
+	SecretDigests []event.SecretDigest
+
 	abort chan struct{}
 	done  chan struct{}
 }
@@ -92,7 +96,7 @@ func (scheduler *scheduler) Start(build turbine.Build) {
 
 	scheduled := &ScheduledBuild{
 		Build:    build,
-		EventHub: event.NewHub(),
+		EventHub: event.NewHub(scheduler.clock),
 
 		abort: make(chan struct{}),
 		done:  make(chan struct{}),
@@ -175,6 +179,18 @@ func (scheduler *scheduler) Abort(guid string) {
 	close(scheduled.abort)
 }
 
+func (scheduler *scheduler) Lookup(guid string) (ScheduledBuild, bool) {
+	scheduler.mutex.RLock()
+	defer scheduler.mutex.RUnlock()
+
+	scheduled, found := scheduler.builds[guid]
+	if !found {
+		return ScheduledBuild{}, false
+	}
+
+	return *scheduled, true
+}
+
 func (scheduler *scheduler) Delete(guid string) {
 	scheduler.mutex.RLock()
 	scheduled, found := scheduler.builds[guid]
@@ -191,7 +207,7 @@ func (scheduler *scheduler) Delete(guid string) {
 	scheduler.mutex.Unlock()
 }
 
-func (scheduler *scheduler) Hijack(guid string, spec gapi.ProcessSpec, io gapi.ProcessIO) (gapi.Process, error) {
+func (scheduler *scheduler) Hijack(guid string, spec runtime.ProcessSpec, io runtime.ProcessIO) (runtime.Process, error) {
 	return scheduler.builder.Hijack(guid, spec, io)
 }
 
@@ -221,12 +237,12 @@ func (scheduler *scheduler) attach(running builder.RunningBuild, scheduled *Sche
 	})
 
 	exited := make(chan builder.ExitedBuild, 1)
-	errored := make(chan error, 1)
+	errored := make(chan erroredBuild, 1)
 
 	go func() {
-		ex, err := scheduler.builder.Attach(running, scheduled.EventHub, scheduled.abort)
+		ex, err := scheduler.builder.Attach(running, scheduled.emitter(), scheduled.abort)
 		if err != nil {
-			errored <- err
+			errored <- erroredBuild{ex, err}
 		} else {
 			exited <- ex
 		}
@@ -234,12 +250,18 @@ func (scheduler *scheduler) attach(running builder.RunningBuild, scheduled *Sche
 
 	select {
 	case build := <-exited:
-		log.Info("exited")
+		log.Info("exited", lager.Data{"status": build.Status})
 
 		scheduler.finish(build, scheduled)
 
-	case err := <-errored:
-		log.Error("errored", err)
+	case errored := <-errored:
+		log.Error("errored", errored.err)
+
+		if errored.exited.Container != nil {
+			// perform outputs that run for errored builds
+			scheduler.finish(errored.exited, scheduled)
+			return
+		}
 
 		select {
 		case <-scheduled.abort:
@@ -250,32 +272,54 @@ func (scheduler *scheduler) attach(running builder.RunningBuild, scheduled *Sche
 
 		scheduled.EventHub.EmitEvent(event.End{})
 	case <-scheduler.draining:
+		// the build keeps running; emit its buffered output so that it's in
+		// the snapshot
+		scheduler.builder.FlushLogs(running.Build.Guid)
 	}
 }
 
+// emitter masks the secrets that the builder can't, as they're known only by
+// digest
+func (scheduled *ScheduledBuild) emitter() event.Emitter {
+	return event.NewDigestMaskingEmitter(scheduled.EventHub, scheduled.SecretDigests)
+}
+
+type erroredBuild struct {
+	exited builder.ExitedBuild
+	err    error
+}
+
 func (scheduler *scheduler) finish(exited builder.ExitedBuild, scheduled *ScheduledBuild) {
 	log := scheduler.logger.Session("finish", lager.Data{
 		"guid": exited.Build.Guid,
 	})
 
-	finished, err := scheduler.builder.Finish(exited, scheduled.EventHub, scheduled.abort)
+	finished, err := scheduler.builder.Finish(exited, scheduled.emitter(), scheduled.abort)
 	if err != nil {
 		log.Error("failed", err)
+		finished = exited.Build
+	} else {
+		log.Info("finished")
+	}
 
+	switch {
+	case exited.Status == turbine.StatusAborted || exited.Status == turbine.StatusErrored:
+		// outputs don't change how an aborted or errored build ended
+		scheduler.updateAndReportBuild(finished, exited.Status)
+
+	case err != nil:
 		select {
 		case <-scheduled.abort:
 			scheduler.updateAndReportBuild(exited.Build, turbine.StatusAborted)
 		default:
 			scheduler.updateAndReportBuild(exited.Build, turbine.StatusErrored)
 		}
-	} else {
-		log.Info("finished")
 
-		if exited.ExitStatus == 0 {
-			scheduler.updateAndReportBuild(finished, turbine.StatusSucceeded)
-		} else {
-			scheduler.updateAndReportBuild(finished, turbine.StatusFailed)
-		}
+	case exited.ExitStatus == 0:
+		scheduler.updateAndReportBuild(finished, turbine.StatusSucceeded)
+
+	default:
+		scheduler.updateAndReportBuild(finished, turbine.StatusFailed)
 	}
 
 	scheduled.EventHub.EmitEvent(event.End{})
@@ -306,11 +350,14 @@ func (scheduler *scheduler) updateAndReportBuild(
 ) {
 	scheduler.mutex.Lock()
 	scheduled := scheduler.builds[build.Guid]
+	// record the build as computed so far, e.g. its fetched inputs and
+	// performed outputs, so that it is snapshotted and reported
+	scheduled.Build = build
 	scheduled.Status = status
 	scheduler.mutex.Unlock()
 
 	scheduled.EventHub.EmitEvent(event.Status{
-		Status: scheduled.Status,
-		Time:   scheduler.clock.CurrentTime().Unix(),
+		Status:    scheduled.Status,
+		Timestamp: event.Timestamp{Time: scheduler.clock.CurrentTime().Unix()},
 	})
 }
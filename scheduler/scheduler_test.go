@@ -9,12 +9,12 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-golang/lager/lagertest"
 
-	garden "github.com/cloudfoundry-incubator/garden/api"
-	gfakes "github.com/cloudfoundry-incubator/garden/api/fakes"
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/builder"
 	bfakes "github.com/concourse/turbine/builder/fakes"
 	"github.com/concourse/turbine/event"
+	"github.com/concourse/turbine/runtime"
+	rtfakes "github.com/concourse/turbine/runtime/fakes"
 	. "github.com/concourse/turbine/scheduler"
 	"github.com/concourse/turbine/scheduler/fakes"
 )
@@ -114,6 +114,29 @@ var _ = Describe("Scheduler", func() {
 				fakeBuilder.StartReturns(running, nil)
 			})
 
+			It("records the build with its fetched inputs", func() {
+				running.Build.Inputs = []turbine.Input{
+					{Name: "some-input", Version: turbine.Version{"ref": "fetched"}},
+				}
+
+				fakeBuilder.StartReturns(running, nil)
+
+				attached := make(chan struct{})
+				fakeBuilder.AttachStub = func(builder.RunningBuild, event.Emitter, <-chan struct{}) (builder.ExitedBuild, error) {
+					<-attached
+					return builder.ExitedBuild{}, errors.New("done")
+				}
+
+				defer close(attached)
+
+				scheduler.Start(build)
+
+				Eventually(func() []turbine.Input {
+					scheduled, _ := scheduler.Lookup(build.Guid)
+					return scheduled.Build.Inputs
+				}).Should(Equal(running.Build.Inputs))
+			})
+
 			It("emits a started status event", func() {
 				scheduler.Start(build)
 
@@ -121,8 +144,8 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-					Status: turbine.StatusStarted,
-					Time:   startTime.Unix(),
+					Status:    turbine.StatusStarted,
+					Timestamp: event.Timestamp{Time: startTime.Unix()},
 				})))
 			})
 
@@ -163,8 +186,8 @@ var _ = Describe("Scheduler", func() {
 						defer close(stop)
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-							Status: turbine.StatusSucceeded,
-							Time:   endTime.Unix(),
+							Status:    turbine.StatusSucceeded,
+							Timestamp: event.Timestamp{Time: endTime.Unix()},
 						})))
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -172,6 +195,45 @@ var _ = Describe("Scheduler", func() {
 					})
 				})
 
+				Context("and the build finishes with computed outputs", func() {
+					var finished turbine.Build
+
+					BeforeEach(func() {
+						finished = exited.Build
+						finished.Outputs = []turbine.Output{
+							{Name: "some-output", Version: turbine.Version{"ref": "performed"}},
+						}
+
+						fakeBuilder.FinishReturns(finished, nil)
+					})
+
+					It("records the finished build", func() {
+						scheduler.Start(build)
+
+						Eventually(func() turbine.Status {
+							scheduled, _ := scheduler.Lookup(build.Guid)
+							return scheduled.Status
+						}).Should(Equal(turbine.StatusSucceeded))
+
+						scheduled, found := scheduler.Lookup(build.Guid)
+						Ω(found).Should(BeTrue())
+						Ω(scheduled.Build).Should(Equal(finished))
+					})
+
+					It("includes it when draining", func() {
+						scheduler.Start(build)
+
+						Eventually(func() turbine.Status {
+							scheduled, _ := scheduler.Lookup(build.Guid)
+							return scheduled.Status
+						}).Should(Equal(turbine.StatusSucceeded))
+
+						drained := scheduler.Drain()
+						Ω(drained).Should(HaveLen(1))
+						Ω(drained[0].Build).Should(Equal(finished))
+					})
+				})
+
 				Context("and the build fails to finish", func() {
 					BeforeEach(func() {
 						fakeBuilder.FinishStub = func(builder.ExitedBuild, event.Emitter, <-chan struct{}) (turbine.Build, error) {
@@ -187,8 +249,8 @@ var _ = Describe("Scheduler", func() {
 						defer close(stop)
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-							Status: turbine.StatusErrored,
-							Time:   endTime.Unix(),
+							Status:    turbine.StatusErrored,
+							Timestamp: event.Timestamp{Time: endTime.Unix()},
 						})))
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -234,8 +296,8 @@ var _ = Describe("Scheduler", func() {
 						defer close(stop)
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-							Status: turbine.StatusFailed,
-							Time:   endTime.Unix(),
+							Status:    turbine.StatusFailed,
+							Timestamp: event.Timestamp{Time: endTime.Unix()},
 						})))
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -258,8 +320,8 @@ var _ = Describe("Scheduler", func() {
 						defer close(stop)
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-							Status: turbine.StatusErrored,
-							Time:   endTime.Unix(),
+							Status:    turbine.StatusErrored,
+							Timestamp: event.Timestamp{Time: endTime.Unix()},
 						})))
 
 						Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -268,6 +330,103 @@ var _ = Describe("Scheduler", func() {
 				})
 			})
 
+			Context("when the build is aborted while running", func() {
+				var exited builder.ExitedBuild
+
+				BeforeEach(func() {
+					exited = builder.ExitedBuild{
+						Build:  running.Build,
+						Status: turbine.StatusAborted,
+					}
+
+					fakeBuilder.AttachReturns(exited, nil)
+
+					fakeBuilder.FinishStub = func(builder.ExitedBuild, event.Emitter, <-chan struct{}) (turbine.Build, error) {
+						clock.CurrentTimeReturns(endTime)
+						return exited.Build, nil
+					}
+				})
+
+				It("finishes the build, so that outputs for aborted builds are performed", func() {
+					scheduler.Start(build)
+
+					emittedEvents, stop := subscribeToBuildEvents()
+					defer close(stop)
+
+					// the build goroutine reads this spec's variables until it ends
+					Eventually(emittedEvents).Should(BeClosed())
+
+					Ω(fakeBuilder.FinishCallCount()).Should(Equal(1))
+
+					completing, _, _ := fakeBuilder.FinishArgsForCall(0)
+					Ω(completing).Should(Equal(exited))
+				})
+
+				It("emits an aborted status event, ends the stream, and closes it", func() {
+					scheduler.Start(build)
+
+					emittedEvents, stop := subscribeToBuildEvents()
+					defer close(stop)
+
+					Eventually(emittedEvents).Should(Receive(Equal(event.Status{
+						Status:    turbine.StatusAborted,
+						Timestamp: event.Timestamp{Time: endTime.Unix()},
+					})))
+
+					Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
+					Eventually(emittedEvents).Should(BeClosed())
+				})
+			})
+
+			Context("when building fails with the build's container", func() {
+				var exited builder.ExitedBuild
+
+				BeforeEach(func() {
+					exited = builder.ExitedBuild{
+						Build:     running.Build,
+						Container: new(rtfakes.FakeContainer),
+						Status:    turbine.StatusErrored,
+					}
+
+					fakeBuilder.AttachReturns(exited, errors.New("oh no!"))
+
+					fakeBuilder.FinishStub = func(builder.ExitedBuild, event.Emitter, <-chan struct{}) (turbine.Build, error) {
+						clock.CurrentTimeReturns(endTime)
+						return exited.Build, nil
+					}
+				})
+
+				It("finishes the build, so that outputs for errored builds are performed", func() {
+					scheduler.Start(build)
+
+					emittedEvents, stop := subscribeToBuildEvents()
+					defer close(stop)
+
+					// the build goroutine reads this spec's variables until it ends
+					Eventually(emittedEvents).Should(BeClosed())
+
+					Ω(fakeBuilder.FinishCallCount()).Should(Equal(1))
+
+					completing, _, _ := fakeBuilder.FinishArgsForCall(0)
+					Ω(completing).Should(Equal(exited))
+				})
+
+				It("emits an errored status event, ends the stream, and closes it", func() {
+					scheduler.Start(build)
+
+					emittedEvents, stop := subscribeToBuildEvents()
+					defer close(stop)
+
+					Eventually(emittedEvents).Should(Receive(Equal(event.Status{
+						Status:    turbine.StatusErrored,
+						Timestamp: event.Timestamp{Time: endTime.Unix()},
+					})))
+
+					Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
+					Eventually(emittedEvents).Should(BeClosed())
+				})
+			})
+
 			Context("when building fails", func() {
 				BeforeEach(func() {
 					fakeBuilder.AttachStub = func(builder.RunningBuild, event.Emitter, <-chan struct{}) (builder.ExitedBuild, error) {
@@ -283,8 +442,8 @@ var _ = Describe("Scheduler", func() {
 					defer close(stop)
 
 					Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-						Status: turbine.StatusErrored,
-						Time:   endTime.Unix(),
+						Status:    turbine.StatusErrored,
+						Timestamp: event.Timestamp{Time: endTime.Unix()},
 					})))
 
 					Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -316,8 +475,8 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-					Status: turbine.StatusErrored,
-					Time:   endTime.Unix(),
+					Status:    turbine.StatusErrored,
+					Timestamp: event.Timestamp{Time: endTime.Unix()},
 				})))
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -335,9 +494,9 @@ var _ = Describe("Scheduler", func() {
 
 		Context("with a completed build", func() {
 			BeforeEach(func() {
-				hub := event.NewHub()
+				hub := event.NewHub(clock)
 				hub.EmitEvent(event.Version("1.0"))
-				hub.EmitEvent(event.Start{Time: 1})
+				hub.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 1}})
 
 				scheduledBuild = ScheduledBuild{
 					Build:     build,
@@ -354,7 +513,7 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(events).Should(Receive(Equal(event.Version("1.0"))))
-				Eventually(events).Should(Receive(Equal(event.Start{Time: 1})))
+				Eventually(events).Should(Receive(Equal(event.Start{Timestamp: event.Timestamp{Time: 1}})))
 			})
 
 			It("closes its event log without emitting a new version", func() {
@@ -364,7 +523,7 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(events).Should(Receive(Equal(event.Version("1.0"))))
-				Eventually(events).Should(Receive(Equal(event.Start{Time: 1})))
+				Eventually(events).Should(Receive(Equal(event.Start{Timestamp: event.Timestamp{Time: 1}})))
 
 				_, ok := <-events
 				Ω(ok).Should(BeFalse())
@@ -377,9 +536,9 @@ var _ = Describe("Scheduler", func() {
 
 		Context("with a started build", func() {
 			BeforeEach(func() {
-				hub := event.NewHub()
+				hub := event.NewHub(clock)
 				hub.EmitEvent(event.Version("1.0"))
-				hub.EmitEvent(event.Start{Time: 1})
+				hub.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 1}})
 
 				scheduledBuild = ScheduledBuild{
 					Build:     build,
@@ -396,7 +555,7 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(events).Should(Receive(Equal(event.Version("1.0"))))
-				Eventually(events).Should(Receive(Equal(event.Start{Time: 1})))
+				Eventually(events).Should(Receive(Equal(event.Start{Timestamp: event.Timestamp{Time: 1}})))
 				Eventually(events).Should(Receive(Equal(event.CURRENT_VERSION)))
 			})
 
@@ -443,11 +602,34 @@ var _ = Describe("Scheduler", func() {
 					}
 
 					Eventually(events).Should(Receive(Equal(event.Status{
-						Status: turbine.StatusSucceeded,
-						Time:   exitedTime.Unix(),
+						Status:    turbine.StatusSucceeded,
+						Timestamp: event.Timestamp{Time: exitedTime.Unix()},
 					})))
 				})
 			})
+
+			Context("with secret digests", func() {
+				BeforeEach(func() {
+					digests, err := event.DigestSecrets([]string{"s3cr3t"})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					scheduledBuild.SecretDigests = digests
+
+					clock.CurrentTimeReturns(time.Unix(42, 0))
+
+					fakeBuilder.AttachStub = func(running builder.RunningBuild, emitter event.Emitter, abort <-chan struct{}) (builder.ExitedBuild, error) {
+						emitter.EmitEvent(event.Log{Payload: "token is s3cr3t"})
+						return builder.ExitedBuild{Build: running.Build}, nil
+					}
+				})
+
+				It("masks them in the build's output", func() {
+					Eventually(scheduledBuild.EventHub.Events).Should(ContainElement(event.Log{
+						Payload:   "token is ((redacted))",
+						Timestamp: event.Timestamp{Time: 42},
+					}))
+				})
+			})
 		})
 	})
 
@@ -460,32 +642,32 @@ var _ = Describe("Scheduler", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := scheduler.Hijack("some-guid", garden.ProcessSpec{}, garden.ProcessIO{})
+				_, err := scheduler.Hijack("some-guid", runtime.ProcessSpec{}, runtime.ProcessIO{})
 				Ω(err).Should(Equal(disaster))
 			})
 		})
 
 		Context("when the builder successfully hijacks", func() {
 			BeforeEach(func() {
-				fakeProcess := new(gfakes.FakeProcess)
+				fakeProcess := new(rtfakes.FakeProcess)
 				fakeProcess.WaitReturns(42, nil)
 
 				fakeBuilder.HijackReturns(fakeProcess, nil)
 			})
 
 			It("hijacks via the builder", func() {
-				spec := garden.ProcessSpec{
+				spec := runtime.ProcessSpec{
 					Path: "process-path",
 					Args: []string{"process", "args"},
-					TTY: &garden.TTYSpec{
-						WindowSize: &garden.WindowSize{
+					TTY: &runtime.TTYSpec{
+						WindowSize: &runtime.WindowSize{
 							Columns: 123,
 							Rows:    456,
 						},
 					},
 				}
 
-				io := garden.ProcessIO{
+				io := runtime.ProcessIO{
 					Stdin:  new(bytes.Buffer),
 					Stdout: new(bytes.Buffer),
 				}
@@ -548,8 +730,8 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-					Status: turbine.StatusAborted,
-					Time:   currentTime.Unix(),
+					Status:    turbine.StatusAborted,
+					Timestamp: event.Timestamp{Time: currentTime.Unix()},
 				})))
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -596,8 +778,8 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-					Status: turbine.StatusAborted,
-					Time:   currentTime.Unix(),
+					Status:    turbine.StatusAborted,
+					Timestamp: event.Timestamp{Time: currentTime.Unix()},
 				})))
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -648,8 +830,8 @@ var _ = Describe("Scheduler", func() {
 				defer close(stop)
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.Status{
-					Status: turbine.StatusAborted,
-					Time:   currentTime.Unix(),
+					Status:    turbine.StatusAborted,
+					Timestamp: event.Timestamp{Time: currentTime.Unix()},
 				})))
 
 				Eventually(emittedEvents).Should(Receive(Equal(event.End{})))
@@ -658,6 +840,13 @@ var _ = Describe("Scheduler", func() {
 		})
 	})
 
+	Describe("Lookup", func() {
+		It("returns false for unknown builds", func() {
+			_, found := scheduler.Lookup("bogus-guid")
+			Ω(found).Should(BeFalse())
+		})
+	})
+
 	Describe("Delete", func() {
 		It("clears out the build's events", func() {
 			scheduler.Start(build)
@@ -722,6 +911,26 @@ var _ = Describe("Scheduler", func() {
 				Ω(drainedBuilds[0].ProcessID).Should(Equal(uint32(42)))
 			})
 
+			It("flushes its buffered logs before returning", func() {
+				scheduler.Start(build)
+
+				drained := make(chan []ScheduledBuild)
+
+				go func() {
+					drained <- scheduler.Drain()
+				}()
+
+				running <- builder.RunningBuild{
+					Build:     build,
+					ProcessID: 42,
+				}
+
+				Eventually(drained).Should(Receive())
+
+				Ω(fakeBuilder.FlushLogsCallCount()).Should(Equal(1))
+				Ω(fakeBuilder.FlushLogsArgsForCall(0)).Should(Equal(build.Guid))
+			})
+
 			It("does not emit an end event, as the stream is not over", func() {
 				scheduler.Start(build)
 
@@ -926,12 +1135,12 @@ var _ = Describe("Scheduler", func() {
 				var emitter event.Emitter
 				Eventually(buildEmitter).Should(Receive(&emitter))
 
-				emitter.EmitEvent(event.Start{Time: 1})
-				Eventually(emittedEvents).Should(Receive(Equal(event.Start{Time: 1})))
+				emitter.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 1}})
+				Eventually(emittedEvents).Should(Receive(Equal(event.Start{Timestamp: event.Timestamp{Time: 1}})))
 
 				close(stop)
 
-				emitter.EmitEvent(event.Start{Time: 2})
+				emitter.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 2}})
 				Consistently(emittedEvents).ShouldNot(Receive())
 			})
 		})
@@ -18,6 +18,7 @@ type Snapshotter struct {
 
 	snapshotPath string
 	scheduler    scheduler.Scheduler
+	clock        event.Clock
 }
 
 type BuildSnapshot struct {
@@ -25,14 +26,18 @@ type BuildSnapshot struct {
 	Status    turbine.Status  `json:"status"`
 	ProcessID uint32          `json:"process_id"`
 	Events    []event.Message `json:"events"`
+
+	// the build's secret params are omitted; these mask its output instead
+	SecretDigests []event.SecretDigest `json:"secret_digests,omitempty"`
 }
 
-func NewSnapshotter(logger lager.Logger, snapshotPath string, scheduler scheduler.Scheduler) *Snapshotter {
+func NewSnapshotter(logger lager.Logger, snapshotPath string, scheduler scheduler.Scheduler, clock event.Clock) *Snapshotter {
 	return &Snapshotter{
 		logger: logger,
 
 		snapshotPath: snapshotPath,
 		scheduler:    scheduler,
+		clock:        clock,
 	}
 }
 
@@ -57,9 +62,9 @@ func (snapshotter *Snapshotter) Run(signals <-chan os.Signal, ready chan<- struc
 					"build-snapshot": snapshot,
 				})
 
-				hub := event.NewHub()
+				hub := event.NewHub(snapshotter.clock)
 				for _, m := range snapshot.Events {
-					hub.EmitEvent(m.Event)
+					hub.Replay(m.Event)
 				}
 
 				if snapshot.Status == "" {
@@ -72,6 +77,8 @@ func (snapshotter *Snapshotter) Run(signals <-chan os.Signal, ready chan<- struc
 					Status:    snapshot.Status,
 					ProcessID: snapshot.ProcessID,
 					EventHub:  hub,
+
+					SecretDigests: snapshot.SecretDigests,
 				})
 			}
 		}
@@ -97,14 +104,23 @@ func (snapshotter *Snapshotter) Run(signals <-chan os.Signal, ready chan<- struc
 	for _, running := range running {
 		msgs := []event.Message{}
 		for _, e := range running.EventHub.Events() {
-			msgs = append(msgs, event.Message{e})
+			msgs = append(msgs, event.Message{Event: e})
+		}
+
+		digests, err := event.DigestSecrets(running.Build.Config.Secrets())
+		if err != nil {
+			log.Error("failed-to-digest-secrets", err)
+			return err
 		}
 
 		snapshots = append(snapshots, BuildSnapshot{
-			Build:     running.Build,
+			Build:     running.Build.Redacted(),
 			Status:    running.Status,
 			ProcessID: running.ProcessID,
 			Events:    msgs,
+
+			// builds restored earlier have only their digests
+			SecretDigests: append(digests, running.SecretDigests...),
 		})
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/concourse/turbine"
 	"github.com/concourse/turbine/event"
+	efakes "github.com/concourse/turbine/event/fakes"
 	sched "github.com/concourse/turbine/scheduler"
 	sfakes "github.com/concourse/turbine/scheduler/fakes"
 	. "github.com/concourse/turbine/snapshotter"
@@ -20,6 +22,7 @@ import (
 var _ = Describe("Snapshotter", func() {
 	var snapshotPath string
 	var scheduler *sfakes.FakeScheduler
+	var clock *efakes.FakeClock
 	var snapshotter *Snapshotter
 
 	var process ifrit.Process
@@ -36,15 +39,18 @@ var _ = Describe("Snapshotter", func() {
 		snapshotPath = snapshotFile.Name()
 
 		scheduler = new(sfakes.FakeScheduler)
-		snapshotter = NewSnapshotter(lagertest.NewTestLogger("test"), snapshotPath, scheduler)
+		clock = new(efakes.FakeClock)
+		clock.CurrentTimeReturns(time.Unix(42, 0))
 
-		firstHub := event.NewHub()
+		snapshotter = NewSnapshotter(lagertest.NewTestLogger("test"), snapshotPath, scheduler, clock)
+
+		firstHub := event.NewHub(clock)
 		firstHub.EmitEvent(event.Version("0.0"))
-		firstHub.EmitEvent(event.Start{Time: 1})
+		firstHub.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 1}})
 
-		secondHub := event.NewHub()
+		secondHub := event.NewHub(clock)
 		secondHub.EmitEvent(event.Version("1.0"))
-		secondHub.EmitEvent(event.Start{Time: 2})
+		secondHub.EmitEvent(event.Start{Timestamp: event.Timestamp{Time: 2}})
 
 		theRunningBuilds = []sched.ScheduledBuild{
 			{
@@ -85,8 +91,8 @@ var _ = Describe("Snapshotter", func() {
 				Status:    turbine.StatusStarted,
 				ProcessID: 123,
 				Events: []event.Message{
-					{event.Version("0.0")},
-					{event.Start{Time: 1}},
+					{Event: event.Version("0.0")},
+					{Event: event.Start{Timestamp: event.Timestamp{Time: 1}}},
 				},
 			},
 			{
@@ -100,8 +106,8 @@ var _ = Describe("Snapshotter", func() {
 				Status:    turbine.StatusSucceeded,
 				ProcessID: 124,
 				Events: []event.Message{
-					{event.Version("1.0")},
-					{event.Start{Time: 2}},
+					{Event: event.Version("1.0")},
+					{Event: event.Start{Timestamp: event.Timestamp{Time: 2}}},
 				},
 			},
 		}
@@ -145,6 +151,59 @@ var _ = Describe("Snapshotter", func() {
 
 				Ω(snapshots).Should(Equal(theSnapshots))
 			})
+
+			Context("when a build has secret params", func() {
+				BeforeEach(func() {
+					theRunningBuilds[0].Build.Config.SecretParams = map[string]string{
+						"TOKEN": "s3cr3t",
+					}
+				})
+
+				It("omits them from the snapshot", func() {
+					Eventually(process.Wait()).Should(Receive(BeNil()))
+
+					snapshot, err := ioutil.ReadFile(snapshotPath)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(string(snapshot)).ShouldNot(ContainSubstring("s3cr3t"))
+
+					var snapshots []BuildSnapshot
+					err = json.Unmarshal(snapshot, &snapshots)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					// digests are salted, so can't be compared
+					Ω(snapshots[0].SecretDigests).Should(HaveLen(1))
+					Ω(snapshots[0].SecretDigests[0].Length).Should(Equal(len("s3cr3t")))
+					snapshots[0].SecretDigests = nil
+
+					Ω(snapshots).Should(Equal(theSnapshots))
+				})
+			})
+
+			Context("when a build was restored with secret digests", func() {
+				var digests []event.SecretDigest
+
+				BeforeEach(func() {
+					var err error
+					digests, err = event.DigestSecrets([]string{"s3cr3t"})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					theRunningBuilds[1].SecretDigests = digests
+				})
+
+				It("keeps them in the snapshot", func() {
+					Eventually(process.Wait()).Should(Receive(BeNil()))
+
+					snapshotFile, err := os.Open(snapshotPath)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					var snapshots []BuildSnapshot
+					err = json.NewDecoder(snapshotFile).Decode(&snapshots)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(snapshots[1].SecretDigests).Should(Equal(digests))
+				})
+			})
 		})
 	})
 
@@ -158,6 +217,31 @@ var _ = Describe("Snapshotter", func() {
 				Ω(err).ShouldNot(HaveOccurred())
 			})
 
+			Context("with secret digests", func() {
+				var digests []event.SecretDigest
+
+				BeforeEach(func() {
+					var err error
+					digests, err = event.DigestSecrets([]string{"s3cr3t"})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					theSnapshots[0].SecretDigests = digests
+
+					snapshot, err := json.Marshal(theSnapshots)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					err = ioutil.WriteFile(snapshotPath, snapshot, 0644)
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+
+				It("restores them with the build, so that its output is masked", func() {
+					Eventually(scheduler.RestoreCallCount).Should(Equal(len(theRunningBuilds)))
+
+					restored := scheduler.RestoreArgsForCall(0)
+					Ω(restored.SecretDigests).Should(Equal(digests))
+				})
+			})
+
 			It("restores the builds to the scheduler", func() {
 				Eventually(scheduler.RestoreCallCount).Should(Equal(len(theRunningBuilds)))
 
@@ -171,6 +255,47 @@ var _ = Describe("Snapshotter", func() {
 			})
 		})
 
+		Context("and it contains events without timestamps", func() {
+			BeforeEach(func() {
+				snapshot, err := json.Marshal([]BuildSnapshot{
+					{
+						Status:    turbine.StatusStarted,
+						ProcessID: 123,
+						Events: []event.Message{
+							{Event: event.Version("1.1")},
+							{Event: event.Log{Payload: "some-log"}},
+						},
+					},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = ioutil.WriteFile(snapshotPath, snapshot, 0644)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("restores them as they were", func() {
+				Eventually(scheduler.RestoreCallCount).Should(Equal(1))
+
+				restored := scheduler.RestoreArgsForCall(0)
+				Ω(restored.EventHub.Events()).Should(Equal([]event.Event{
+					event.Version("1.1"),
+					event.Log{Payload: "some-log"},
+				}))
+			})
+
+			It("timestamps events emitted after restoring", func() {
+				Eventually(scheduler.RestoreCallCount).Should(Equal(1))
+
+				restored := scheduler.RestoreArgsForCall(0)
+				restored.EventHub.EmitEvent(event.Log{Payload: "new-log"})
+
+				Ω(restored.EventHub.Events()).Should(ContainElement(event.Log{
+					Payload:   "new-log",
+					Timestamp: event.Timestamp{Time: 42},
+				}))
+			})
+		})
+
 		Context("and it contains builds with no status", func() {
 			BeforeEach(func() {
 				snapshot, err := json.Marshal([]BuildSnapshot{
@@ -185,8 +310,8 @@ var _ = Describe("Snapshotter", func() {
 						Status:    "",
 						ProcessID: 123,
 						Events: []event.Message{
-							{event.Version("0.0")},
-							{event.Start{Time: 1}},
+							{Event: event.Version("0.0")},
+							{Event: event.Start{Timestamp: event.Timestamp{Time: 1}}},
 						},
 					},
 				})
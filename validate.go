@@ -0,0 +1,212 @@
+package turbine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists every problem found with a build, so that they can
+// all be fixed at once.
+type ValidationError struct {
+	Problems []string `json:"problems"`
+}
+
+func (err ValidationError) Error() string {
+	return "invalid build: " + strings.Join(err.Problems, "; ")
+}
+
+// Validate checks for problems that would prevent the build from running.
+// If any input provides a config file, the image and run config may still
+// come from it, so they are not required.
+func (build Build) Validate() error {
+	return validationError(build.problems(!build.providesConfig()))
+}
+
+// ValidateMerged checks the build once config from its inputs has been
+// merged in, at which point the config must be complete.
+func (build Build) ValidateMerged() error {
+	return validationError(build.problems(true))
+}
+
+// Validate checks that the config is complete enough to run.
+func (config Config) Validate() error {
+	return validationError(config.problems())
+}
+
+func (build Build) providesConfig() bool {
+	for _, input := range build.Inputs {
+		if input.ConfigPath != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (build Build) problems(requireConfig bool) []string {
+	problems := []string{}
+
+	if requireConfig {
+		problems = append(problems, build.Config.problems()...)
+	}
+
+	inputNames := map[string]bool{}
+	for i, input := range build.Inputs {
+		if input.Name == "" {
+			problems = append(problems, fmt.Sprintf("input #%d has no name", i+1))
+		} else if inputNames[input.Name] {
+			problems = append(problems, fmt.Sprintf("input '%s' is specified more than once", input.Name))
+		}
+
+		if input.Type == "" {
+			problems = append(problems, fmt.Sprintf("input '%s' has no type", input.Name))
+		}
+
+		for _, problem := range input.Retry.problems() {
+			problems = append(problems, fmt.Sprintf("input '%s' has %s", input.Name, problem))
+		}
+
+		if input.Timeout < 0 {
+			problems = append(problems, fmt.Sprintf("input '%s' has negative timeout", input.Name))
+		}
+
+		inputNames[input.Name] = true
+	}
+
+	for _, input := range build.Config.Inputs {
+		if !inputNames[input.Name] {
+			problems = append(problems, fmt.Sprintf("config refers to unknown input '%s'", input.Name))
+		}
+	}
+
+	outputNames := map[string]bool{}
+	for _, output := range build.Outputs {
+		outputNames[output.Name] = true
+	}
+
+	seenOutputs := map[string]bool{}
+	for i, output := range build.Outputs {
+		if output.Name == "" {
+			problems = append(problems, fmt.Sprintf("output #%d has no name", i+1))
+		} else if seenOutputs[output.Name] {
+			problems = append(problems, fmt.Sprintf("output '%s' is specified more than once", output.Name))
+		}
+
+		seenOutputs[output.Name] = true
+
+		if output.Type == "" {
+			problems = append(problems, fmt.Sprintf("output '%s' has no type", output.Name))
+		}
+
+		for _, condition := range output.On {
+			if !condition.IsValid() {
+				problems = append(problems, fmt.Sprintf("output '%s' has unknown condition '%s'", output.Name, condition))
+			}
+		}
+
+		for _, problem := range output.Retry.problems() {
+			problems = append(problems, fmt.Sprintf("output '%s' has %s", output.Name, problem))
+		}
+
+		if output.Timeout < 0 {
+			problems = append(problems, fmt.Sprintf("output '%s' has negative timeout", output.Name))
+		}
+
+		dependencies := map[string]bool{}
+		for _, dependency := range output.DependsOn {
+			if !outputNames[dependency] {
+				problems = append(problems, fmt.Sprintf("output '%s' depends on unknown output '%s'", output.Name, dependency))
+			}
+
+			dependencies[dependency] = true
+		}
+
+		for _, reference := range output.OutputReferences() {
+			if !dependencies[reference] {
+				problems = append(problems, fmt.Sprintf("output '%s' refers to output '%s' without depending on it", output.Name, reference))
+			}
+		}
+	}
+
+	if cycle := outputDependencyCycle(build.Outputs); cycle != nil {
+		problems = append(problems, fmt.Sprintf("outputs have a dependency cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
+	return problems
+}
+
+// outputDependencyCycle returns the names along a cycle in the outputs'
+// dependencies, if there is one, ending with the name it started with.
+func outputDependencyCycle(outputs []Output) []string {
+	dependsOn := make(map[string][]string, len(outputs))
+	for _, output := range outputs {
+		dependsOn[output.Name] = output.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(outputs))
+	path := []string{}
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dependency := range dependsOn[name] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, output := range outputs {
+		if cycle := visit(output.Name); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+func (config Config) problems() []string {
+	problems := []string{}
+
+	if config.Image == "" {
+		problems = append(problems, "no image specified")
+	}
+
+	if config.Run.Path == "" {
+		problems = append(problems, "no run path specified")
+	}
+
+	return problems
+}
+
+func validationError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return ValidationError{Problems: problems}
+}
@@ -0,0 +1,10 @@
+package turbine
+
+// subscribes to new versions of an input's resource, checked for on the
+// given interval
+type WatchRequest struct {
+	// the source to check, and optionally the version to check from
+	Input Input `json:"input"`
+
+	Interval Duration `json:"interval"`
+}